@@ -1,21 +1,97 @@
 package streamingjsongo
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
+	"unicode/utf8"
 )
 
 type Lexer struct {
-	JSONContent      strings.Builder // input JSON content
-	PaddingContent   strings.Builder // padding content for ignored characters and escape characters, etc.
-	JSONSegment      string          // appended JSON segment by the AppendString() method.
-	TokenStack       []int           // token stack for input JSON
-	MirrorTokenStack []int           // token stack for auto-completed tokens
+	JSONContent      bytes.Buffer // input JSON content, it was strings.Builder, see Breaking Changes in README for migration
+	PaddingContent   bytes.Buffer // padding content for ignored characters and escape characters, etc., it was strings.Builder too
+	JSONSegment      string       // rest of the JSON segment appended by AppendString() not lexed yet, it is consumed byte by byte.
+	TokenStack       []int        // token stack for input JSON, holds open containers and tokens of the member in lexing
+	MirrorTokenStack []int        // token stack for auto-completed tokens
+
+	options      lexerOptions   // options given by NewLexer()
+	grammar      grammar        // grammar of input JSON
+	grammarError *SyntaxError   // first grammar error of input JSON in lenient mode
+	position     streamPosition // position of the next byte in JSON stream
+	segmentCount int            // count of appended JSON segments
+	value        *valueBuilder  // builder of partial value, created by the first Value() call
+	handler      handlerAdapter // adapter of the handler given by WithHandler()
+	paths        *pathTracker   // tracker of current path and subscriptions, created by the first subscription or Context() call
+	stableTaken  int            // length of stable prefix taken by TakeStable()
+	number       numberLexer    // lexer of the number in lexing
+	surrogate    bool           // a high surrogate escape is kept in padding content until its low surrogate arrives
+	runePending  bool           // an incomplete multi-byte UTF-8 sequence of a string is kept in padding content
+	atomicPaths  []atomicPath   // atomic values at paths given by SetAtomic()
+	extractor    jsonExtractor  // extractor of the JSON embedded in text, given by WithExtractEmbedded()
+	relaxed      jsonNormalizer // normalizer of relaxed JSON, given by WithRelaxed()
+	python       pythonRewriter // rewriter of Python literals, given by WithPythonLiterals()
+	repair       stringRepairer // repairer of strings, given by WithRepair()
+
+	documents       []json.RawMessage         // completed documents in multiple documents mode
+	onDocument      func(raw json.RawMessage) // callback of completed documents given by OnDocument()
+	onDocumentError func(err *SyntaxError)    // callback of dropped documents given by OnDocumentError()
+	skipLine        bool                      // the rest of the line of a dropped document is skipped
+	continuedLine   bool                      // the line after a newline breaking a string continues the dropped document
+
+	mirrorTokens       []byte // cached symbols of mirror stack in completion order
+	mirrorTokensCached bool   // if cached symbols of mirror stack are up to date
+	completionTail     []byte // reused buffer of the completed partial number or hidden value and closing tokens
+	completedStable    int    // length of stable prefix kept by CompleteJSONIncremental()
 }
 
 // new lexer for streaming JSON input
-func NewLexer() *Lexer {
-	return &Lexer{}
+func NewLexer(options ...Option) *Lexer {
+	lexer := &Lexer{}
+	for _, option := range options {
+		option(lexer)
+	}
+	lexer.resetListeners()
+	return lexer
+}
+
+// reset the lexer for a new JSON stream, the allocated memory is kept for reuse
+func (lexer *Lexer) Reset() {
+	lexer.resetDocument()
+	lexer.JSONSegment = ""
+	lexer.grammarError = nil
+	lexer.position = streamPosition{}
+	lexer.segmentCount = 0
+	lexer.documents = nil
+	lexer.skipLine = false
+	lexer.continuedLine = false
+	lexer.extractor.reset()
+	lexer.resetStages()
+}
+
+// reset the stages normalizing the JSON stream before lexing
+func (lexer *Lexer) resetStages() {
+	lexer.relaxed.reset()
+	lexer.python.reset()
+	lexer.repair.reset()
+}
+
+// reset the lexer for the next document of JSON stream, the position in stream and completed documents are kept
+func (lexer *Lexer) resetDocument() {
+	lexer.JSONContent.Reset()
+	lexer.PaddingContent.Reset()
+	lexer.TokenStack = lexer.TokenStack[:0]
+	lexer.MirrorTokenStack = lexer.MirrorTokenStack[:0]
+	lexer.mirrorTokensCached = false
+	lexer.grammar.reset()
+	lexer.value = nil
+	lexer.resetListeners()
+	lexer.stableTaken = 0
+	lexer.completedStable = 0
+	lexer.number.end()
+	lexer.surrogate = false
+	lexer.runePending = false
 }
 
 // get token on the stack top
@@ -55,6 +131,7 @@ func (lexer *Lexer) popMirrorTokenStack() int {
 	}
 	token := lexer.MirrorTokenStack[mirrorTokenStackLen-1]
 	lexer.MirrorTokenStack = lexer.MirrorTokenStack[:mirrorTokenStackLen-1]
+	lexer.mirrorTokensCached = false
 	return token
 }
 
@@ -63,28 +140,46 @@ func (lexer *Lexer) pushTokenStack(token int) {
 	lexer.TokenStack = append(lexer.TokenStack, token)
 }
 
+// find the innermost open container on the stack, returns its index, or -1 if not found
+func (lexer *Lexer) findContainerOnStack() int {
+	for i := len(lexer.TokenStack) - 1; i >= 0; i-- {
+		if lexer.TokenStack[i] == TOKEN_LEFT_BRACE || lexer.TokenStack[i] == TOKEN_LEFT_BRACKET {
+			return i
+		}
+	}
+	return -1
+}
+
+// pop tokens of the member in the innermost open container from the stack, and keep the container
+// so the stack only holds open containers and tokens of the member in lexing, it grows with depth but not with input size
+func (lexer *Lexer) popMemberFromTokenStack() {
+	lexer.TokenStack = lexer.TokenStack[:lexer.findContainerOnStack()+1]
+}
+
+// pop the innermost open container from the stack when it closed, with the member containing it
+func (lexer *Lexer) popContainerFromTokenStack() {
+	lexer.TokenStack = lexer.TokenStack[:lexer.findContainerOnStack()+1]
+	lexer.popTokenStack()
+	lexer.popMemberFromTokenStack()
+}
+
 // push token into the mirror stack
 func (lexer *Lexer) pushMirrorTokenStack(token int) {
 	lexer.MirrorTokenStack = append(lexer.MirrorTokenStack, token)
+	lexer.mirrorTokensCached = false
 }
 
-// convert mirror stack token into string
-func (lexer *Lexer) dumpMirrorTokenStackToString() string {
-	var stackInString strings.Builder
+// convert mirror stack token into bytes, the result is cached until the mirror stack changes
+func (lexer *Lexer) dumpMirrorTokenStack() []byte {
+	if lexer.mirrorTokensCached {
+		return lexer.mirrorTokens
+	}
+	lexer.mirrorTokens = lexer.mirrorTokens[:0]
 	for i := len(lexer.MirrorTokenStack) - 1; i >= 0; i-- {
-		stackInString.WriteString(tokenSymbolMap[lexer.MirrorTokenStack[i]])
+		lexer.mirrorTokens = append(lexer.mirrorTokens, tokenSymbolMap[lexer.MirrorTokenStack[i]]...)
 	}
-	return stackInString.String()
-}
-
-// skip JSON segment by length n
-func (lexer *Lexer) skipJSONSegment(n int) {
-	lexer.JSONSegment = lexer.JSONSegment[n:]
-}
-
-// push negative symbol `-` into JSON content
-func (lexer *Lexer) pushNegativeIntoJSONContent() {
-	lexer.JSONContent.WriteByte(TOKEN_NEGATIVE_SYMBOL)
+	lexer.mirrorTokensCached = true
+	return lexer.mirrorTokens
 }
 
 // push byte into JSON content by given
@@ -94,7 +189,13 @@ func (lexer *Lexer) pushByteIntoPaddingContent(b byte) {
 
 // append padding content into JSON content
 func (lexer *Lexer) appendPaddingContentToJSONContent() {
-	lexer.JSONContent.WriteString(lexer.PaddingContent.String())
+	if lexer.surrogate {
+		// the high surrogate kept is not followed by its low surrogate
+		lexer.JSONContent.WriteString(replacementCharacterEscape)
+		lexer.PaddingContent.Next(unicodeEscapeLength)
+		lexer.surrogate = false
+	}
+	lexer.JSONContent.Write(lexer.PaddingContent.Bytes())
 }
 
 // check if padding content is empty
@@ -107,1052 +208,1192 @@ func (lexer *Lexer) cleanPaddingContent() {
 	lexer.PaddingContent.Reset()
 }
 
-// lexer match JSON token method, convert JSON segment to JSON token
-func (lexer *Lexer) matchToken() (int, byte) {
-	// segment end
-	if len(lexer.JSONSegment) == 0 {
-		return TOKEN_EOF, byte(0)
-	}
-	tokenSymbol := lexer.JSONSegment[0]
-
+// lexer match JSON token method, convert JSON token symbol to JSON token
+func (lexer *Lexer) matchToken(tokenSymbol byte) int {
 	// check if ignored token
 	if isIgnoreToken(tokenSymbol) {
-		lexer.skipJSONSegment(1)
-		return TOKEN_IGNORED, tokenSymbol
+		return TOKEN_IGNORED
 	}
 
 	// match token
 	switch tokenSymbol {
 	case TOKEN_LEFT_BRACKET_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_LEFT_BRACKET, tokenSymbol
+		return TOKEN_LEFT_BRACKET
 	case TOKEN_RIGHT_BRACKET_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_RIGHT_BRACKET, tokenSymbol
+		return TOKEN_RIGHT_BRACKET
 	case TOKEN_LEFT_BRACE_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_LEFT_BRACE, tokenSymbol
+		return TOKEN_LEFT_BRACE
 	case TOKEN_RIGHT_BRACE_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_RIGHT_BRACE, tokenSymbol
+		return TOKEN_RIGHT_BRACE
 	case TOKEN_COLON_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_COLON, tokenSymbol
+		return TOKEN_COLON
 	case TOKEN_DOT_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_DOT, tokenSymbol
+		return TOKEN_DOT
 	case TOKEN_COMMA_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_COMMA, tokenSymbol
+		return TOKEN_COMMA
 	case TOKEN_QUOTE_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_QUOTE, tokenSymbol
+		return TOKEN_QUOTE
 	case TOKEN_ESCAPE_CHARACTER_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ESCAPE_CHARACTER, tokenSymbol
+		return TOKEN_ESCAPE_CHARACTER
 	case TOKEN_SLASH_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_SLASH, tokenSymbol
+		return TOKEN_SLASH
 	case TOKEN_NEGATIVE_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_NEGATIVE, tokenSymbol
+		return TOKEN_NEGATIVE
 	case TOKEN_ALPHABET_LOWERCASE_A_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_LOWERCASE_A, tokenSymbol
+		return TOKEN_ALPHABET_LOWERCASE_A
 	case TOKEN_ALPHABET_LOWERCASE_B_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_LOWERCASE_B, tokenSymbol
+		return TOKEN_ALPHABET_LOWERCASE_B
 	case TOKEN_ALPHABET_LOWERCASE_C_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_LOWERCASE_C, tokenSymbol
+		return TOKEN_ALPHABET_LOWERCASE_C
 	case TOKEN_ALPHABET_LOWERCASE_D_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_LOWERCASE_D, tokenSymbol
+		return TOKEN_ALPHABET_LOWERCASE_D
 	case TOKEN_ALPHABET_LOWERCASE_E_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_LOWERCASE_E, tokenSymbol
+		return TOKEN_ALPHABET_LOWERCASE_E
 	case TOKEN_ALPHABET_LOWERCASE_F_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_LOWERCASE_F, tokenSymbol
+		return TOKEN_ALPHABET_LOWERCASE_F
 	case TOKEN_ALPHABET_LOWERCASE_L_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_LOWERCASE_L, tokenSymbol
+		return TOKEN_ALPHABET_LOWERCASE_L
 	case TOKEN_ALPHABET_LOWERCASE_N_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_LOWERCASE_N, tokenSymbol
+		return TOKEN_ALPHABET_LOWERCASE_N
 	case TOKEN_ALPHABET_LOWERCASE_R_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_LOWERCASE_R, tokenSymbol
+		return TOKEN_ALPHABET_LOWERCASE_R
 	case TOKEN_ALPHABET_LOWERCASE_S_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_LOWERCASE_S, tokenSymbol
+		return TOKEN_ALPHABET_LOWERCASE_S
 	case TOKEN_ALPHABET_LOWERCASE_T_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_LOWERCASE_T, tokenSymbol
+		return TOKEN_ALPHABET_LOWERCASE_T
 	case TOKEN_ALPHABET_LOWERCASE_U_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_LOWERCASE_U, tokenSymbol
+		return TOKEN_ALPHABET_LOWERCASE_U
 	case TOKEN_ALPHABET_UPPERCASE_A_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_UPPERCASE_A, tokenSymbol
+		return TOKEN_ALPHABET_UPPERCASE_A
 	case TOKEN_ALPHABET_UPPERCASE_B_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_UPPERCASE_B, tokenSymbol
+		return TOKEN_ALPHABET_UPPERCASE_B
 	case TOKEN_ALPHABET_UPPERCASE_C_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_UPPERCASE_C, tokenSymbol
+		return TOKEN_ALPHABET_UPPERCASE_C
 	case TOKEN_ALPHABET_UPPERCASE_D_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_UPPERCASE_D, tokenSymbol
+		return TOKEN_ALPHABET_UPPERCASE_D
 	case TOKEN_ALPHABET_UPPERCASE_E_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_UPPERCASE_E, tokenSymbol
+		return TOKEN_ALPHABET_UPPERCASE_E
 	case TOKEN_ALPHABET_UPPERCASE_F_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_ALPHABET_UPPERCASE_F, tokenSymbol
+		return TOKEN_ALPHABET_UPPERCASE_F
 	case TOKEN_NUMBER_0_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_NUMBER_0, tokenSymbol
+		return TOKEN_NUMBER_0
 	case TOKEN_NUMBER_1_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_NUMBER_1, tokenSymbol
+		return TOKEN_NUMBER_1
 	case TOKEN_NUMBER_2_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_NUMBER_2, tokenSymbol
+		return TOKEN_NUMBER_2
 	case TOKEN_NUMBER_3_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_NUMBER_3, tokenSymbol
+		return TOKEN_NUMBER_3
 	case TOKEN_NUMBER_4_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_NUMBER_4, tokenSymbol
+		return TOKEN_NUMBER_4
 	case TOKEN_NUMBER_5_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_NUMBER_5, tokenSymbol
+		return TOKEN_NUMBER_5
 	case TOKEN_NUMBER_6_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_NUMBER_6, tokenSymbol
+		return TOKEN_NUMBER_6
 	case TOKEN_NUMBER_7_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_NUMBER_7, tokenSymbol
+		return TOKEN_NUMBER_7
 	case TOKEN_NUMBER_8_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_NUMBER_8, tokenSymbol
+		return TOKEN_NUMBER_8
 	case TOKEN_NUMBER_9_SYMBOL:
-		lexer.skipJSONSegment(1)
-		return TOKEN_NUMBER_9, tokenSymbol
+		return TOKEN_NUMBER_9
 	default:
-		lexer.skipJSONSegment(1)
-		return TOKEN_OTHERS, tokenSymbol
+		return TOKEN_OTHERS
 	}
 }
 
-// append JSON string to current JSON stream content
-func (lexer *Lexer) AppendString(str string) error {
-	return lexer.appendString(str)
+// new syntax error for the token symbol at given position, the excerpt is filled by caller
+func (lexer *Lexer) newSyntaxError(msg string, position streamPosition, tokenSymbol byte) *SyntaxError {
+	return &SyntaxError{
+		Msg:     msg,
+		Offset:  position.offset,
+		Line:    position.line + 1,
+		Column:  position.column + 1,
+		Byte:    tokenSymbol,
+		Segment: lexer.segmentCount - 1,
+	}
 }
 
 // append JSON string to current JSON stream content
-// this method will traversal all token and generate mirror token for complete full JSON
-func (lexer *Lexer) appendString(str string) error {
-	lexer.JSONSegment = str
-	for {
-		token, tokenSymbol := lexer.matchToken()
-
-		switch token {
-		case TOKEN_EOF:
-			// nothing to do with TOKEN_EOF
-		case TOKEN_IGNORED:
-			if lexer.streamStoppedInAString() {
-				lexer.JSONContent.WriteByte(tokenSymbol)
-				continue
-			}
-			lexer.pushByteIntoPaddingContent(tokenSymbol)
+func (lexer *Lexer) AppendString(str string) error {
+	_, err := lexer.appendString(str)
+	return err
+}
 
-		case TOKEN_OTHERS:
-			// check if json stream stopped with padding content
-			if lexer.havePaddingContent() {
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
-			}
+// append JSON bytes to current JSON stream content, the bytes are not retained by the lexer
+func (lexer *Lexer) AppendBytes(b []byte) error {
+	_, err := lexer.appendBytes(b)
+	return err
+}
 
-			// write current token symbol to JSON content
-			lexer.JSONContent.WriteByte(tokenSymbol)
+// write JSON bytes to current JSON stream content, implements io.Writer
+func (lexer *Lexer) Write(p []byte) (int, error) {
+	return lexer.appendBytes(p)
+}
 
-		case TOKEN_LEFT_BRACKET:
+// write JSON string to current JSON stream content, implements io.StringWriter
+func (lexer *Lexer) WriteString(s string) (int, error) {
+	return lexer.appendString(s)
+}
 
-			// check if json stream stopped with padding content
-			if lexer.havePaddingContent() {
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
-			}
-			lexer.JSONContent.WriteByte(tokenSymbol)
-			if lexer.streamStoppedInAString() {
-				continue
-			}
-			lexer.pushTokenStack(token)
-			if lexer.streamStoppedInAnObjectArrayValueStart() {
-				// pop `n`, `u`, `l`, `l` from mirror stack
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
+// append JSON string to current JSON stream content
+// this method will traversal all token and generate mirror token for complete full JSON, returns count of appended bytes
+func (lexer *Lexer) appendString(str string) (int, error) {
+	lexer.segmentCount++
+	for i := 0; i < len(str); i++ {
+		lexer.JSONSegment = str[i+1:]
+		if syntaxError := lexer.appendByte(str[i]); syntaxError != nil {
+			excerptStart, excerptEnd := excerptRange(len(str), i)
+			syntaxError.Excerpt = str[excerptStart:excerptEnd]
+			if syntaxError != lexer.grammarError {
+				// the rejected byte and the bytes after it are not appended
+				lexer.JSONSegment = str[i:]
+				lexer.finishSegment(i)
+				return i, syntaxError
 			}
+		}
+	}
+	lexer.JSONSegment = ""
+	lexer.finishSegment(len(str))
+	return len(str), nil
+}
 
-			// push `]` into mirror stack
-			lexer.pushMirrorTokenStack(TOKEN_RIGHT_BRACKET)
-
-		case TOKEN_RIGHT_BRACKET:
-			if lexer.streamStoppedInAString() {
-				lexer.JSONContent.WriteByte(tokenSymbol)
-				continue
+// append JSON bytes to current JSON stream content, returns count of appended bytes
+func (lexer *Lexer) appendBytes(b []byte) (int, error) {
+	lexer.JSONSegment = ""
+	lexer.segmentCount++
+	for i := 0; i < len(b); i++ {
+		if syntaxError := lexer.appendByte(b[i]); syntaxError != nil {
+			excerptStart, excerptEnd := excerptRange(len(b), i)
+			syntaxError.Excerpt = string(b[excerptStart:excerptEnd])
+			if syntaxError != lexer.grammarError {
+				lexer.finishSegment(i)
+				return i, syntaxError
 			}
+		}
+	}
+	lexer.finishSegment(len(b))
+	return len(b), nil
+}
 
-			// check if json stream stopped with padding content
-			if lexer.havePaddingContent() {
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
-			}
+// notify the handler and subscriptions of the changes in current segment, with count of appended bytes
+func (lexer *Lexer) finishSegment(appended int) {
+	if appended == 0 {
+		return
+	}
+	if lexer.options.handler != nil {
+		lexer.handler.flush()
+	}
+	if lexer.paths != nil {
+		lexer.dispatchUpdates()
+	}
+}
 
-			// write current token symbol to JSON content
-			lexer.JSONContent.WriteByte(tokenSymbol)
+// append a byte of JSON stream
+// this method will match the token and generate mirror token for complete full JSON
+func (lexer *Lexer) appendByte(tokenSymbol byte) *SyntaxError {
+	// keep position of current token symbol for reporting syntax error
+	position := lexer.position
+	lexer.position.advance(tokenSymbol)
 
-			// push `]` into stack
-			lexer.pushTokenStack(token)
-			// pop `]` from mirror stack
-			lexer.popMirrorTokenStack()
+	// skip the text around embedded JSON
+	if lexer.options.extractEmbedded && !lexer.extractByte(tokenSymbol) {
+		return nil
+	}
 
-		case TOKEN_LEFT_BRACE:
-			// check if json stream stopped with padding content
-			if lexer.havePaddingContent() {
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
-			}
+	// skip the rest of the line of a dropped document, the next document starts after the newline
+	if lexer.skipLine {
+		lexer.skipLine = tokenSymbol != '\n'
+		return nil
+	}
 
-			// write current token symbol to JSON content
-			lexer.JSONContent.WriteByte(tokenSymbol)
+	// normalize relaxed JSON, rewrite Python literals and repair strings into strict JSON before lexing,
+	// the normalized bytes are lexed at the position of the byte
+	grammarError := lexer.grammarError
+	var syntaxError *SyntaxError
+	switch {
+	case lexer.options.relaxed:
+		syntaxError = lexer.normalizeByte(tokenSymbol, position)
+	case lexer.options.pythonLiterals:
+		syntaxError = lexer.rewriteByte(tokenSymbol, position)
+	case lexer.options.repair:
+		syntaxError = lexer.repairByte(tokenSymbol, position)
+	default:
+		return lexer.lexByte(tokenSymbol, position)
+	}
+	if syntaxError != nil {
+		return syntaxError
+	}
+	// the first grammar error in lenient mode recorded by the byte
+	if lexer.grammarError != grammarError {
+		return lexer.grammarError
+	}
+	return nil
+}
 
-			if lexer.streamStoppedInAString() {
-				continue
-			}
-			lexer.pushTokenStack(token)
+// lex the bytes of strict JSON, returns the error rejecting them, grammar errors in lenient mode are recorded only
+func (lexer *Lexer) lexRepaired(position streamPosition, repaired ...byte) *SyntaxError {
+	for _, c := range repaired {
+		if syntaxError := lexer.lexByte(c, position); syntaxError != nil && syntaxError != lexer.grammarError {
+			return syntaxError
+		}
+	}
+	return nil
+}
 
-			if lexer.streamStoppedInAnObjectObjectValueStart() {
-				// pop `n`, `u`, `l`, `l` from mirror stack
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-			}
+// lex a byte of strict JSON at given position of JSON stream
+func (lexer *Lexer) lexByte(tokenSymbol byte, position streamPosition) *SyntaxError {
+	// the rest of normalized bytes of a dropped document are skipped
+	if lexer.skipLine {
+		return nil
+	}
+	if lexer.value != nil {
+		lexer.value.removeHeldValue()
+	}
+	token := lexer.matchToken(tokenSymbol)
 
-			// push `}` into mirror stack
-			lexer.pushMirrorTokenStack(TOKEN_RIGHT_BRACE)
+	// ignored tokens between documents are dropped in multiple documents mode
+	if lexer.options.multiDocument && token == TOKEN_IGNORED && lexer.JSONContent.Len() == 0 {
+		if tokenSymbol == '\n' {
+			lexer.continuedLine = false
+		}
+		return nil
+	}
 
-		case TOKEN_RIGHT_BRACE:
-			if lexer.streamStoppedInAString() {
-				lexer.JSONContent.WriteByte(tokenSymbol)
-				continue
-			}
+	// reject invalid UTF-8 in strings before the byte changes anything
+	if lexer.options.validUTF8 && !lexer.validRuneByte(tokenSymbol) {
+		return lexer.newSyntaxError(fmt.Sprintf("invalid UTF-8 byte 0x%02x in string", tokenSymbol), position, tokenSymbol)
+	}
 
-			// check if json stream stopped with padding content
-			if lexer.havePaddingContent() {
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
-			}
-			lexer.JSONContent.WriteByte(tokenSymbol)
+	// track grammar before the token changes anything, the byte is rejected in strict mode,
+	// or the first grammar error is recorded and returned by Value() in lenient mode
+	var grammarError *SyntaxError
+	if lexer.grammarError == nil && !lexer.grammar.feed(tokenSymbol) {
+		grammarError = lexer.newSyntaxError(fmt.Sprintf("unexpected token symbol `%c` in json stream", tokenSymbol), position, tokenSymbol)
+		// the text like `{tool}` is not the embedded JSON, search it again from the byte
+		if lexer.options.extractEmbedded && lexer.falseBodyStart() {
+			lexer.notifyError(grammarError)
+			return lexer.restartExtraction(tokenSymbol, position)
+		}
+		// the broken document is dropped in multiple documents mode, also in strict mode, so the following documents
+		// of the segment are still lexed
+		if lexer.options.multiDocument {
+			lexer.dropDocument(grammarError, tokenSymbol)
+			return nil
+		}
+		if lexer.options.strict {
+			return grammarError
+		}
+		lexer.grammarError = grammarError
+		lexer.notifyError(grammarError)
+	}
 
-			// push `}` into stack
-			lexer.pushTokenStack(token)
-			// pop `}` from mirror stack
-			lexer.popMirrorTokenStack()
+	syntaxError := lexer.lexToken(token, tokenSymbol, position)
+	if lexer.paths != nil && len(lexer.paths.completions) > 0 {
+		lexer.dispatchCompletions()
+	}
+	if lexer.grammar.state == grammarStateDone {
+		if lexer.options.extractEmbedded {
+			lexer.endExtractedValue()
+		}
+		if lexer.options.multiDocument {
+			lexer.finishDocument()
+		}
+	}
+	if syntaxError != nil {
+		return syntaxError
+	}
+	return grammarError
+}
 
-		case TOKEN_QUOTE:
-			// check if escape quote `\"`
-			if lexer.streamStoppedWithLeadingEscapeCharacter() {
-				// push padding escape character `\` into JSON content
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
+// start a number by its first byte `-` or digit, the following bytes of it are lexed by the number lexer,
+// and the partial number is completed by appendCompletedNumber() instead of mirror tokens
+func (lexer *Lexer) startNumber(tokenSymbol byte) {
+	lexer.number.begin(tokenSymbol, lexer.JSONContent.Len())
+	lexer.JSONContent.WriteByte(tokenSymbol)
+	lexer.pushTokenStack(TOKEN_NUMBER)
+
+	// the number replaces the `null` placeholder of an object value
+	if !lexer.streamStoppedInAnArray() && lexer.streamStoppedInAnObjectNullValuePlaceholderStart() {
+		// pop `n`, `u`, `l`, `l` from mirror stack
+		lexer.popMirrorTokenStack()
+		lexer.popMirrorTokenStack()
+		lexer.popMirrorTokenStack()
+		lexer.popMirrorTokenStack()
+	}
+}
 
-				// write current token symbol to JSON content
-				lexer.JSONContent.WriteByte(tokenSymbol)
+// lex a byte in the unicode escape of a string, the escape is kept in padding content until its 4 hex digits arrived.
+// a high surrogate is kept until its low surrogate arrives, so the completed JSON never ends with half of a surrogate pair,
+// and a lone surrogate is replaced by `\ufffd`, which keeps the length of JSON content and decodes to the same rune
+func (lexer *Lexer) lexUnicodeEscape(token int, tokenSymbol byte, position streamPosition) *SyntaxError {
+	keptLen := 0
+	if lexer.surrogate {
+		keptLen = unicodeEscapeLength
+	}
+	if !isHexDigit(tokenSymbol) {
+		// invalid unicode escape in an invalid stream, drop it
+		lexer.PaddingContent.Truncate(keptLen)
+		// pop `\`, `u` from stack
+		lexer.popTokenStack()
+		lexer.popTokenStack()
+		if lexer.surrogate {
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
+		}
+		return lexer.lexToken(token, tokenSymbol, position)
+	}
+	lexer.pushByteIntoPaddingContent(tokenSymbol)
+	escape := lexer.PaddingContent.Bytes()[keptLen:]
+	// check if unicode escape is full length
+	if len(escape) < unicodeEscapeLength {
+		return nil
+	}
+	// pop `\`, `u` from stack
+	lexer.popTokenStack()
+	lexer.popTokenStack()
+	value := unicodeEscapeValue(escape)
+	if lexer.surrogate {
+		if isLowSurrogate(value) {
+			// the surrogate pair is complete
+			lexer.surrogate = false
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
+			return nil
+		}
+		// flush the lone high surrogate, then the escape is checked alone
+		lexer.JSONContent.WriteString(replacementCharacterEscape)
+		lexer.PaddingContent.Next(unicodeEscapeLength)
+		lexer.surrogate = false
+	}
+	switch {
+	case isHighSurrogate(value):
+		lexer.surrogate = true
+	case isLowSurrogate(value):
+		lexer.JSONContent.WriteString(replacementCharacterEscape)
+		lexer.cleanPaddingContent()
+	default:
+		lexer.appendPaddingContentToJSONContent()
+		lexer.cleanPaddingContent()
+	}
+	return nil
+}
 
-				// pop `\` from  stack
-				lexer.popTokenStack()
-				continue
-			}
+// check if the byte keeps the UTF-8 sequences of current string valid, bytes out of strings are not checked
+func (lexer *Lexer) validRuneByte(tokenSymbol byte) bool {
+	if !lexer.runePending {
+		return tokenSymbol < utf8.RuneSelf || isRuneStart(tokenSymbol) || !lexer.streamStoppedInAString()
+	}
+	var sequence [utf8.UTFMax]byte
+	sequenceLen := copy(sequence[:], lexer.PaddingContent.Bytes())
+	sequence[sequenceLen] = tokenSymbol
+	sequenceLen++
+	if !utf8.FullRune(sequence[:sequenceLen]) {
+		return true
+	}
+	_, size := utf8.DecodeRune(sequence[:sequenceLen])
+	return size == sequenceLen
+}
 
-			// check if json stream stopped with padding content
-			if lexer.havePaddingContent() {
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
-			}
+// lex a byte of multi-byte UTF-8 sequence in a string, the sequence is kept in padding content until it is complete,
+// so the completed JSON never ends with a part of a character
+func (lexer *Lexer) lexRuneByte(token int, tokenSymbol byte, position streamPosition) *SyntaxError {
+	if lexer.runePending && !isRuneContinuation(tokenSymbol) {
+		// the sequence is truncated in an invalid stream, keep it as it is
+		lexer.runePending = false
+		lexer.appendPaddingContentToJSONContent()
+		lexer.cleanPaddingContent()
+		return lexer.lexToken(token, tokenSymbol, position)
+	}
+	lexer.pushByteIntoPaddingContent(tokenSymbol)
+	lexer.runePending = !utf8.FullRune(lexer.PaddingContent.Bytes())
+	if !lexer.runePending {
+		lexer.appendPaddingContentToJSONContent()
+		lexer.cleanPaddingContent()
+	}
+	return nil
+}
 
-			// write current token symbol to JSON content
+// lex the matched token, generate mirror token for complete full JSON
+func (lexer *Lexer) lexToken(token int, tokenSymbol byte, position streamPosition) *SyntaxError {
+	// in a number, the number lexer decides if the byte continues it, or the number ends before the byte
+	if lexer.number.open() {
+		switch lexer.number.next(tokenSymbol) {
+		case numberAccepted:
 			lexer.JSONContent.WriteByte(tokenSymbol)
-			lexer.pushTokenStack(token)
-			if lexer.streamStoppedInAnArray() {
-				// push `"` into mirror stack
-				lexer.pushMirrorTokenStack(TOKEN_QUOTE)
-
-			} else if lexer.streamStoppedInAnArrayStringValueEnd() {
-				// pop `"` from mirror stack
-				lexer.popMirrorTokenStack()
-
-			} else if lexer.streamStoppedInAnObjectKeyStart() {
-				// check if stopped in key of object's properity or value of object's properity
-				// push `"`, `:`, `n`, `u`, `l`, `l` into mirror stack
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_L)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_L)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_U)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_N)
-				lexer.pushMirrorTokenStack(TOKEN_COLON)
-				lexer.pushMirrorTokenStack(TOKEN_QUOTE)
-
-			} else if lexer.streamStoppedInAnObjectKeyEnd() {
-				// check if stopped in key of object's properity or value of object's properity
-				// pop `"` from mirror stack
-				lexer.popMirrorTokenStack()
-
-			} else if lexer.streamStoppedInAnObjectStringValueStart() {
-				// pop `n`, `u`, `l`, `l` from mirror stack
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				// push `"` into mirror stack
-				lexer.pushMirrorTokenStack(TOKEN_QUOTE)
-
-			} else if lexer.streamStoppedInAnObjectValueEnd() {
-				// pop `"` from mirror stack
-				lexer.popMirrorTokenStack()
-
-			} else {
-				return fmt.Errorf("invalied quote token in json stream")
+			return nil
+		case numberRejected:
+			if !isNumberByte(tokenSymbol) {
+				// the partial number is ended by other token in an invalid stream, like `-1.215e,`, keep it completed
+				completedNumber := appendCompletedNumber(nil, lexer.JSONContent.Bytes()[lexer.number.start:])
+				lexer.JSONContent.Truncate(lexer.number.start)
+				lexer.JSONContent.Write(completedNumber)
 			}
-		case TOKEN_COLON:
+		}
+		lexer.number.end()
+	}
 
-			if lexer.streamStoppedInAString() {
-				lexer.JSONContent.WriteByte(tokenSymbol)
-				continue
-			}
+	// in a unicode escape of a string, like `\u00`
+	if lexer.streamStoppedInAnStringUnicodeEscape() {
+		return lexer.lexUnicodeEscape(token, tokenSymbol, position)
+	}
 
-			// check if json stream stopped with padding content
-			if lexer.havePaddingContent() {
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
-			}
+	// the high surrogate kept is not followed by an escape, like `\ud83d,`
+	if lexer.surrogate && lexer.PaddingContent.Len() == unicodeEscapeLength && tokenSymbol != TOKEN_ESCAPE_CHARACTER_SYMBOL {
+		lexer.appendPaddingContentToJSONContent()
+		lexer.cleanPaddingContent()
+	}
 
-			// write current token symbol to JSON content
+	// in a multi-byte UTF-8 sequence of a string, like the first byte of `中`
+	if lexer.runePending || (isRuneStart(tokenSymbol) && lexer.streamStoppedInAString()) {
+		return lexer.lexRuneByte(token, tokenSymbol, position)
+	}
+
+	switch token {
+	case TOKEN_IGNORED:
+		if lexer.streamStoppedInAString() {
 			lexer.JSONContent.WriteByte(tokenSymbol)
+			return nil
+		}
+		lexer.pushByteIntoPaddingContent(tokenSymbol)
 
-			lexer.pushTokenStack(token)
+	case TOKEN_OTHERS:
+		// check if json stream stopped with padding content
+		if lexer.havePaddingContent() {
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
+		}
+
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
 
-			// pop `:` from mirror stack
+	case TOKEN_LEFT_BRACKET:
+
+		// check if json stream stopped with padding content
+		if lexer.havePaddingContent() {
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
+		}
+		lexer.JSONContent.WriteByte(tokenSymbol)
+		if lexer.streamStoppedInAString() {
+			return nil
+		}
+		lexer.pushTokenStack(token)
+		if lexer.streamStoppedInAnObjectArrayValueStart() {
+			// pop `n`, `u`, `l`, `l` from mirror stack
 			lexer.popMirrorTokenStack()
-		case TOKEN_ALPHABET_LOWERCASE_A:
-
-			// as hex in unicode
-			if lexer.streamStoppedInAnStringUnicodeEscape() {
-				lexer.pushByteIntoPaddingContent(tokenSymbol)
-				// check if unicode escape is full length
-				if lexer.PaddingContent.Len() == 6 {
-					lexer.appendPaddingContentToJSONContent()
-					lexer.cleanPaddingContent()
-					// pop `\`, `u` from stack
-					lexer.popTokenStack()
-					lexer.popTokenStack()
-				}
-				continue
-			}
+			lexer.popMirrorTokenStack()
+			lexer.popMirrorTokenStack()
+			lexer.popMirrorTokenStack()
+		}
 
-			// write current token symbol to JSON content
+		// push `]` into mirror stack
+		lexer.pushMirrorTokenStack(TOKEN_RIGHT_BRACKET)
+
+	case TOKEN_RIGHT_BRACKET:
+		if lexer.streamStoppedInAString() {
 			lexer.JSONContent.WriteByte(tokenSymbol)
+			return nil
+		}
 
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				continue
-			}
+		// check if json stream stopped with padding content
+		if lexer.havePaddingContent() {
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
+		}
 
-			// check if `f` in token stack and `a`, `l`, `s`, `e in mirror stack
-			itIsPartOfTokenFalse := func() bool {
-				left := []int{
-					TOKEN_ALPHABET_LOWERCASE_F,
-				}
-				right := []int{
-					TOKEN_ALPHABET_LOWERCASE_E,
-					TOKEN_ALPHABET_LOWERCASE_S,
-					TOKEN_ALPHABET_LOWERCASE_L,
-					TOKEN_ALPHABET_LOWERCASE_A,
-				}
-				return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
-			}
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
 
-			if !itIsPartOfTokenFalse() {
-				continue
-			}
+		// pop the closed array and the member containing it from stack
+		lexer.popContainerFromTokenStack()
+		// pop `]` from mirror stack
+		lexer.popMirrorTokenStack()
 
-			lexer.pushTokenStack(token)
-			lexer.popMirrorTokenStack()
-		case TOKEN_ALPHABET_LOWERCASE_B:
-
-			// as hex in unicode
-			if lexer.streamStoppedInAnStringUnicodeEscape() {
-				lexer.pushByteIntoPaddingContent(tokenSymbol)
-				// check if unicode escape is full length
-				if lexer.PaddingContent.Len() == 6 {
-					lexer.appendPaddingContentToJSONContent()
-					lexer.cleanPaddingContent()
-					// pop `\`, `u` from stack
-					lexer.popTokenStack()
-					lexer.popTokenStack()
-				}
-				continue
-			}
+	case TOKEN_LEFT_BRACE:
+		// check if json stream stopped with padding content
+		if lexer.havePaddingContent() {
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
+		}
 
-			// \b escape `\`, `b`
-			if lexer.streamStoppedWithLeadingEscapeCharacter() {
-				// push padding escape character `\` into JSON content
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
 
-				// write current token symbol to JSON content
-				lexer.JSONContent.WriteByte(tokenSymbol)
+		if lexer.streamStoppedInAString() {
+			return nil
+		}
+		lexer.pushTokenStack(token)
 
-				// pop `\` from  stack
-				lexer.popTokenStack()
-				continue
-			}
+		if lexer.streamStoppedInAnObjectObjectValueStart() {
+			// pop `n`, `u`, `l`, `l` from mirror stack
+			lexer.popMirrorTokenStack()
+			lexer.popMirrorTokenStack()
+			lexer.popMirrorTokenStack()
+			lexer.popMirrorTokenStack()
+		}
 
-			// write current token symbol to JSON content
+		// push `}` into mirror stack
+		lexer.pushMirrorTokenStack(TOKEN_RIGHT_BRACE)
+
+	case TOKEN_RIGHT_BRACE:
+		if lexer.streamStoppedInAString() {
 			lexer.JSONContent.WriteByte(tokenSymbol)
+			return nil
+		}
 
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				continue
-			}
-		case TOKEN_ALPHABET_LOWERCASE_E:
-
-			// as hex in unicode
-			if lexer.streamStoppedInAnStringUnicodeEscape() {
-				lexer.pushByteIntoPaddingContent(tokenSymbol)
-				// check if unicode escape is full length
-				if lexer.PaddingContent.Len() == 6 {
-					lexer.appendPaddingContentToJSONContent()
-					lexer.cleanPaddingContent()
-					// pop `\`, `u` from stack
-					lexer.popTokenStack()
-					lexer.popTokenStack()
-				}
-				continue
-			}
+		// check if json stream stopped with padding content
+		if lexer.havePaddingContent() {
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
+		}
+		lexer.JSONContent.WriteByte(tokenSymbol)
 
-			// check if in a number, as `e` (exponent) in scientific notation
-			if lexer.streamStoppedInANumberDecimalPartMiddle() {
-				lexer.pushByteIntoPaddingContent(tokenSymbol)
-				continue
-			}
+		// pop the closed object and the member containing it from stack
+		lexer.popContainerFromTokenStack()
+		// pop `}` from mirror stack
+		lexer.popMirrorTokenStack()
+
+	case TOKEN_QUOTE:
+		// check if escape quote `\"`
+		if lexer.streamStoppedWithLeadingEscapeCharacter() {
+			// push padding escape character `\` into JSON content
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
 
 			// write current token symbol to JSON content
 			lexer.JSONContent.WriteByte(tokenSymbol)
 
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				continue
-			}
+			// pop `\` from  stack
+			lexer.popTokenStack()
+			return nil
+		}
 
-			// check if `f`, `a`, `l`, `s` in token stack and `e` in mirror stack
-			itIsPartOfTokenFalse := func() bool {
-				left := []int{
-					TOKEN_ALPHABET_LOWERCASE_F,
-					TOKEN_ALPHABET_LOWERCASE_A,
-					TOKEN_ALPHABET_LOWERCASE_L,
-					TOKEN_ALPHABET_LOWERCASE_S,
-				}
-
-				right := []int{
-					TOKEN_ALPHABET_LOWERCASE_E,
-				}
-				return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
-			}
+		// check if json stream stopped with padding content
+		if lexer.havePaddingContent() {
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
+		}
 
-			// check if `t`, `r`, `u` in token stack and `e` in mirror stack
-			itIsPartOfTokenTrue := func() bool {
-				left := []int{
-					TOKEN_ALPHABET_LOWERCASE_T,
-					TOKEN_ALPHABET_LOWERCASE_R,
-					TOKEN_ALPHABET_LOWERCASE_U,
-				}
-				right := []int{
-					TOKEN_ALPHABET_LOWERCASE_E,
-				}
-				return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
-			}
-			if !itIsPartOfTokenFalse() && !itIsPartOfTokenTrue() {
-				continue
-			}
-			lexer.pushTokenStack(token)
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
+		lexer.pushTokenStack(token)
+		if lexer.streamStoppedInAnArray() {
+			// push `"` into mirror stack
+			lexer.pushMirrorTokenStack(TOKEN_QUOTE)
+
+		} else if lexer.streamStoppedInAnArrayStringValueEnd() {
+			// pop `"` from mirror stack
 			lexer.popMirrorTokenStack()
-		case TOKEN_ALPHABET_LOWERCASE_F:
-
-			// as hex in unicode
-			if lexer.streamStoppedInAnStringUnicodeEscape() {
-				lexer.pushByteIntoPaddingContent(tokenSymbol)
-				// check if unicode escape is full length
-				if lexer.PaddingContent.Len() == 6 {
-					lexer.appendPaddingContentToJSONContent()
-					lexer.cleanPaddingContent()
-					// pop `\`, `u` from stack
-					lexer.popTokenStack()
-					lexer.popTokenStack()
-				}
-				continue
-			}
 
-			// \f escape `\`, `f`
-			if lexer.streamStoppedWithLeadingEscapeCharacter() {
-				// push padding escape character `\` into JSON content
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
+		} else if lexer.streamStoppedInAnObjectKeyStart() {
+			// check if stopped in key of object's properity or value of object's properity
+			// push `"`, `:`, `n`, `u`, `l`, `l` into mirror stack
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_L)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_L)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_U)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_N)
+			lexer.pushMirrorTokenStack(TOKEN_COLON)
+			lexer.pushMirrorTokenStack(TOKEN_QUOTE)
+
+		} else if lexer.streamStoppedInAnObjectKeyEnd() {
+			// check if stopped in key of object's properity or value of object's properity
+			// pop `"` from mirror stack
+			lexer.popMirrorTokenStack()
 
-				// write current token symbol to JSON content
-				lexer.JSONContent.WriteByte(tokenSymbol)
+		} else if lexer.streamStoppedInAnObjectStringValueStart() {
+			// pop `n`, `u`, `l`, `l` from mirror stack
+			lexer.popMirrorTokenStack()
+			lexer.popMirrorTokenStack()
+			lexer.popMirrorTokenStack()
+			lexer.popMirrorTokenStack()
+			// push `"` into mirror stack
+			lexer.pushMirrorTokenStack(TOKEN_QUOTE)
 
-				// pop `\` from  stack
-				lexer.popTokenStack()
-				continue
-			}
+		} else if lexer.streamStoppedInAnObjectValueEnd() {
+			// pop `"` from mirror stack
+			lexer.popMirrorTokenStack()
 
-			// check if json stream stopped with padding content, like case `[true , f`
-			if lexer.havePaddingContent() {
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
-			}
+		} else if lexer.streamStoppedInATopLevelStringValueStart() {
+			// push `"` into mirror stack
+			lexer.pushMirrorTokenStack(TOKEN_QUOTE)
 
-			// write current token symbol to JSON content
-			lexer.JSONContent.WriteByte(tokenSymbol)
+		} else if lexer.streamStoppedInATopLevelStringValueEnd() {
+			// pop `"` from mirror stack
+			lexer.popMirrorTokenStack()
 
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				continue
-			}
+		} else {
+			return lexer.newSyntaxError("invalid quote token in json stream", position, tokenSymbol)
+		}
+	case TOKEN_COLON:
 
-			// push `f` into stack
-			lexer.pushTokenStack(token)
-			if lexer.streamStoppedInAnArray() {
-				// in array
-				// push `a`, `l`, `s`, `e`
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_E)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_S)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_L)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_A)
-			} else {
-				// in object
-				// pop `n`, `u`, `l`, `l`
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				// push `a`, `l`, `s`, `e`
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_E)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_S)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_L)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_A)
-			}
-		case TOKEN_ALPHABET_LOWERCASE_L:
-			// write current token symbol to JSON content
+		if lexer.streamStoppedInAString() {
 			lexer.JSONContent.WriteByte(tokenSymbol)
+			return nil
+		}
 
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				continue
-			}
+		// check if json stream stopped with padding content
+		if lexer.havePaddingContent() {
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
+		}
 
-			// check if `f`, `a` in token stack and, `l`, `s`, `e` in mirror stack
-			itIsPartOfTokenFalse := func() bool {
-				left := []int{
-					TOKEN_ALPHABET_LOWERCASE_F,
-					TOKEN_ALPHABET_LOWERCASE_A,
-				}
-				right := []int{
-					TOKEN_ALPHABET_LOWERCASE_E,
-					TOKEN_ALPHABET_LOWERCASE_S,
-					TOKEN_ALPHABET_LOWERCASE_L,
-				}
-				return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
-			}
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
 
-			// check if `n`, `u` in token stack and `l`, `l` in mirror stack
-			itIsPartOfTokenNull1 := func() bool {
-				left := []int{
-					TOKEN_ALPHABET_LOWERCASE_N,
-					TOKEN_ALPHABET_LOWERCASE_U,
-				}
-				right := []int{
-					TOKEN_ALPHABET_LOWERCASE_L,
-					TOKEN_ALPHABET_LOWERCASE_L,
-				}
-				return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
-			}
+		lexer.pushTokenStack(token)
 
-			// check if `n`, `u`, `l` in token stack and `l` in mirror stack
-			itIsPartOfTokenNull2 := func() bool {
-				left := []int{
-					TOKEN_ALPHABET_LOWERCASE_N,
-					TOKEN_ALPHABET_LOWERCASE_U,
-					TOKEN_ALPHABET_LOWERCASE_L,
-				}
-				right := []int{
-					TOKEN_ALPHABET_LOWERCASE_L,
-				}
-				return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
-			}
-			if !itIsPartOfTokenFalse() && !itIsPartOfTokenNull1() && !itIsPartOfTokenNull2() {
-				continue
-			}
-			lexer.pushTokenStack(token)
-			lexer.popMirrorTokenStack()
+		// pop `:` from mirror stack
+		lexer.popMirrorTokenStack()
+	case TOKEN_ALPHABET_LOWERCASE_A:
 
-		case TOKEN_ALPHABET_LOWERCASE_N:
-			// \n escape `\`, `n`
-			if lexer.streamStoppedWithLeadingEscapeCharacter() {
-				// push padding escape character `\` into JSON content
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
 
-				// write current token symbol to JSON content
-				lexer.JSONContent.WriteByte(tokenSymbol)
+		// in a string, just skip token
+		if lexer.streamStoppedInAString() {
+			return nil
+		}
 
-				// pop `\` from  stack
-				lexer.popTokenStack()
-				continue
+		// check if `f` in token stack and `a`, `l`, `s`, `e in mirror stack
+		itIsPartOfTokenFalse := func() bool {
+			left := []int{
+				TOKEN_ALPHABET_LOWERCASE_F,
 			}
-
-			// check if json stream stopped with padding content, like case `[true , n`
-			if lexer.havePaddingContent() {
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
+			right := []int{
+				TOKEN_ALPHABET_LOWERCASE_E,
+				TOKEN_ALPHABET_LOWERCASE_S,
+				TOKEN_ALPHABET_LOWERCASE_L,
+				TOKEN_ALPHABET_LOWERCASE_A,
 			}
+			return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
+		}
+
+		if !itIsPartOfTokenFalse() {
+			return nil
+		}
+
+		lexer.pushTokenStack(token)
+		lexer.popMirrorTokenStack()
+	case TOKEN_ALPHABET_LOWERCASE_B:
+
+		// \b escape `\`, `b`
+		if lexer.streamStoppedWithLeadingEscapeCharacter() {
+			// push padding escape character `\` into JSON content
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
 
 			// write current token symbol to JSON content
 			lexer.JSONContent.WriteByte(tokenSymbol)
 
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				continue
-			}
+			// pop `\` from  stack
+			lexer.popTokenStack()
+			return nil
+		}
 
-			// push `n`
-			lexer.pushTokenStack(token)
-			if lexer.streamStoppedInAnArray() {
-				// in array, push `u`, `l`, `l`
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_L)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_L)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_U)
-			} else {
-				// in object, pop `n`
-				lexer.popMirrorTokenStack()
-			}
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
 
-		case TOKEN_ALPHABET_LOWERCASE_R:
-			// \r escape `\`, `r`
-			if lexer.streamStoppedWithLeadingEscapeCharacter() {
-				// push padding escape character `\` into JSON content
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
+		// in a string, just skip token
+		if lexer.streamStoppedInAString() {
+			return nil
+		}
+	case TOKEN_ALPHABET_LOWERCASE_E:
 
-				// write current token symbol to JSON content
-				lexer.JSONContent.WriteByte(tokenSymbol)
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
 
-				// pop `\` from  stack
-				lexer.popTokenStack()
-				continue
-			}
+		// in a string, just skip token
+		if lexer.streamStoppedInAString() {
+			return nil
+		}
 
-			// write current token symbol to JSON content
-			lexer.JSONContent.WriteByte(tokenSymbol)
+		// check if `f`, `a`, `l`, `s` in token stack and `e` in mirror stack
+		itIsPartOfTokenFalse := func() bool {
+			left := []int{
+				TOKEN_ALPHABET_LOWERCASE_F,
+				TOKEN_ALPHABET_LOWERCASE_A,
+				TOKEN_ALPHABET_LOWERCASE_L,
+				TOKEN_ALPHABET_LOWERCASE_S,
+			}
 
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				continue
+			right := []int{
+				TOKEN_ALPHABET_LOWERCASE_E,
 			}
+			return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
+		}
 
-			// check if `t` in token stack and `r`, `u`, `e in mirror stack
-			itIsPartOfTokenTrue := func() bool {
-				left := []int{
-					TOKEN_ALPHABET_LOWERCASE_T,
-				}
-				right := []int{
-					TOKEN_ALPHABET_LOWERCASE_E,
-					TOKEN_ALPHABET_LOWERCASE_U,
-					TOKEN_ALPHABET_LOWERCASE_R,
-				}
-				return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
+		// check if `t`, `r`, `u` in token stack and `e` in mirror stack
+		itIsPartOfTokenTrue := func() bool {
+			left := []int{
+				TOKEN_ALPHABET_LOWERCASE_T,
+				TOKEN_ALPHABET_LOWERCASE_R,
+				TOKEN_ALPHABET_LOWERCASE_U,
 			}
-			if !itIsPartOfTokenTrue() {
-				continue
+			right := []int{
+				TOKEN_ALPHABET_LOWERCASE_E,
 			}
-			lexer.pushTokenStack(token)
-			lexer.popMirrorTokenStack()
+			return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
+		}
+		if !itIsPartOfTokenFalse() && !itIsPartOfTokenTrue() {
+			return nil
+		}
+		lexer.pushTokenStack(token)
+		lexer.popMirrorTokenStack()
+	case TOKEN_ALPHABET_LOWERCASE_F:
+
+		// \f escape `\`, `f`
+		if lexer.streamStoppedWithLeadingEscapeCharacter() {
+			// push padding escape character `\` into JSON content
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
 
-		case TOKEN_ALPHABET_LOWERCASE_S:
 			// write current token symbol to JSON content
 			lexer.JSONContent.WriteByte(tokenSymbol)
 
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				continue
-			}
+			// pop `\` from  stack
+			lexer.popTokenStack()
+			return nil
+		}
 
-			// check if `f`, `a`, `l` in token stack and `s`, `e in mirror stack
-			itIsPartOfTokenFalse := func() bool {
-				left := []int{
-					TOKEN_ALPHABET_LOWERCASE_F,
-					TOKEN_ALPHABET_LOWERCASE_A,
-					TOKEN_ALPHABET_LOWERCASE_L,
-				}
-				right := []int{
-					TOKEN_ALPHABET_LOWERCASE_E,
-					TOKEN_ALPHABET_LOWERCASE_S,
-				}
-				return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
-			}
-			if !itIsPartOfTokenFalse() {
-				continue
-			}
-			lexer.pushTokenStack(token)
+		// check if json stream stopped with padding content, like case `[true , f`
+		if lexer.havePaddingContent() {
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
+		}
+
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
+
+		// in a string, just skip token
+		if lexer.streamStoppedInAString() {
+			return nil
+		}
+
+		// push `f` into stack
+		lexer.pushTokenStack(token)
+		if lexer.streamStoppedInAnArray() {
+			// in array
+			// push `a`, `l`, `s`, `e`
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_E)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_S)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_L)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_A)
+		} else {
+			// in object
+			// pop `n`, `u`, `l`, `l`
+			lexer.popMirrorTokenStack()
+			lexer.popMirrorTokenStack()
 			lexer.popMirrorTokenStack()
-		case TOKEN_ALPHABET_LOWERCASE_T:
+			lexer.popMirrorTokenStack()
+			// push `a`, `l`, `s`, `e`
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_E)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_S)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_L)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_A)
+		}
+	case TOKEN_ALPHABET_LOWERCASE_L:
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
 
-			// \t escape `\`, `t`
-			if lexer.streamStoppedWithLeadingEscapeCharacter() {
-				// push padding escape character `\` into JSON content
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
+		// in a string, just skip token
+		if lexer.streamStoppedInAString() {
+			return nil
+		}
 
-				// write current token symbol to JSON content
-				lexer.JSONContent.WriteByte(tokenSymbol)
+		// check if `f`, `a` in token stack and, `l`, `s`, `e` in mirror stack
+		itIsPartOfTokenFalse := func() bool {
+			left := []int{
+				TOKEN_ALPHABET_LOWERCASE_F,
+				TOKEN_ALPHABET_LOWERCASE_A,
+			}
+			right := []int{
+				TOKEN_ALPHABET_LOWERCASE_E,
+				TOKEN_ALPHABET_LOWERCASE_S,
+				TOKEN_ALPHABET_LOWERCASE_L,
+			}
+			return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
+		}
 
-				// pop `\` from  stack
-				lexer.popTokenStack()
-				continue
+		// check if `n`, `u` in token stack and `l`, `l` in mirror stack
+		itIsPartOfTokenNull1 := func() bool {
+			left := []int{
+				TOKEN_ALPHABET_LOWERCASE_N,
+				TOKEN_ALPHABET_LOWERCASE_U,
 			}
+			right := []int{
+				TOKEN_ALPHABET_LOWERCASE_L,
+				TOKEN_ALPHABET_LOWERCASE_L,
+			}
+			return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
+		}
 
-			// check if json stream stopped with padding content, like case `[true , t`
-			if lexer.havePaddingContent() {
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
+		// check if `n`, `u`, `l` in token stack and `l` in mirror stack
+		itIsPartOfTokenNull2 := func() bool {
+			left := []int{
+				TOKEN_ALPHABET_LOWERCASE_N,
+				TOKEN_ALPHABET_LOWERCASE_U,
+				TOKEN_ALPHABET_LOWERCASE_L,
+			}
+			right := []int{
+				TOKEN_ALPHABET_LOWERCASE_L,
 			}
+			return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
+		}
+		if !itIsPartOfTokenFalse() && !itIsPartOfTokenNull1() && !itIsPartOfTokenNull2() {
+			return nil
+		}
+		lexer.pushTokenStack(token)
+		lexer.popMirrorTokenStack()
+
+	case TOKEN_ALPHABET_LOWERCASE_N:
+		// \n escape `\`, `n`
+		if lexer.streamStoppedWithLeadingEscapeCharacter() {
+			// push padding escape character `\` into JSON content
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
 
 			// write current token symbol to JSON content
 			lexer.JSONContent.WriteByte(tokenSymbol)
 
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				continue
-			}
+			// pop `\` from  stack
+			lexer.popTokenStack()
+			return nil
+		}
 
-			// push `t` to stack
-			lexer.pushTokenStack(token)
-			if lexer.streamStoppedInAnArray() {
-				// in array
-				// push `r`, `u`, `e`
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_E)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_U)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_R)
-			} else {
-				// in object
-				// pop `n`, `u`, `l`, `l`
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				// push `r`, `u`, `e`
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_E)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_U)
-				lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_R)
-			}
-		case TOKEN_ALPHABET_LOWERCASE_U:
+		// check if json stream stopped with padding content, like case `[true , n`
+		if lexer.havePaddingContent() {
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
+		}
 
-			// unicode escape `\`, `u`
-			if lexer.streamStoppedWithLeadingEscapeCharacter() {
-				lexer.pushTokenStack(token)
-				lexer.PaddingContent.WriteByte(tokenSymbol)
-				continue
-			}
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
+
+		// in a string, just skip token
+		if lexer.streamStoppedInAString() {
+			return nil
+		}
+
+		// push `n`
+		lexer.pushTokenStack(token)
+		if lexer.streamStoppedInAnArray() {
+			// in array, push `u`, `l`, `l`
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_L)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_L)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_U)
+		} else {
+			// in object, pop `n`
+			lexer.popMirrorTokenStack()
+		}
+
+	case TOKEN_ALPHABET_LOWERCASE_R:
+		// \r escape `\`, `r`
+		if lexer.streamStoppedWithLeadingEscapeCharacter() {
+			// push padding escape character `\` into JSON content
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
 
 			// write current token symbol to JSON content
 			lexer.JSONContent.WriteByte(tokenSymbol)
 
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				continue
-			}
+			// pop `\` from  stack
+			lexer.popTokenStack()
+			return nil
+		}
 
-			// check if `t`, `r` in token stack and, `u`, `e` in mirror stack
-			itIsPartOfTokenTrue := func() bool {
-				left := []int{
-					TOKEN_ALPHABET_LOWERCASE_T,
-					TOKEN_ALPHABET_LOWERCASE_R,
-				}
-				right := []int{
-					TOKEN_ALPHABET_LOWERCASE_E,
-					TOKEN_ALPHABET_LOWERCASE_U,
-				}
-				return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
-			}
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
 
-			// check if `n` in token stack and `u`, `l`, `l` in mirror stack
-			itIsPartOfTokenNull := func() bool {
-				left := []int{
-					TOKEN_ALPHABET_LOWERCASE_N,
-				}
-				right := []int{
-					TOKEN_ALPHABET_LOWERCASE_L,
-					TOKEN_ALPHABET_LOWERCASE_L,
-					TOKEN_ALPHABET_LOWERCASE_U,
-				}
-				return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
-			}
-			if !itIsPartOfTokenTrue() && !itIsPartOfTokenNull() {
-				continue
+		// in a string, just skip token
+		if lexer.streamStoppedInAString() {
+			return nil
+		}
+
+		// check if `t` in token stack and `r`, `u`, `e in mirror stack
+		itIsPartOfTokenTrue := func() bool {
+			left := []int{
+				TOKEN_ALPHABET_LOWERCASE_T,
 			}
-			lexer.pushTokenStack(token)
-			lexer.popMirrorTokenStack()
-		case TOKEN_ALPHABET_UPPERCASE_A:
-			fallthrough
-		case TOKEN_ALPHABET_UPPERCASE_B:
-			fallthrough
-		case TOKEN_ALPHABET_UPPERCASE_C:
-			fallthrough
-		case TOKEN_ALPHABET_UPPERCASE_D:
-			fallthrough
-		case TOKEN_ALPHABET_LOWERCASE_C:
-			fallthrough
-		case TOKEN_ALPHABET_LOWERCASE_D:
-			fallthrough
-		case TOKEN_ALPHABET_UPPERCASE_F:
-
-			// as hex in unicode
-			if lexer.streamStoppedInAnStringUnicodeEscape() {
-				lexer.pushByteIntoPaddingContent(tokenSymbol)
-				// check if unicode escape is full length
-				if lexer.PaddingContent.Len() == 6 {
-					lexer.appendPaddingContentToJSONContent()
-					lexer.cleanPaddingContent()
-					// pop `\`, `u` from stack
-					lexer.popTokenStack()
-					lexer.popTokenStack()
-				}
-				continue
+			right := []int{
+				TOKEN_ALPHABET_LOWERCASE_E,
+				TOKEN_ALPHABET_LOWERCASE_U,
+				TOKEN_ALPHABET_LOWERCASE_R,
 			}
+			return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
+		}
+		if !itIsPartOfTokenTrue() {
+			return nil
+		}
+		lexer.pushTokenStack(token)
+		lexer.popMirrorTokenStack()
 
-			// write current token symbol to JSON content
-			lexer.JSONContent.WriteByte(tokenSymbol)
+	case TOKEN_ALPHABET_LOWERCASE_S:
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
+
+		// in a string, just skip token
+		if lexer.streamStoppedInAString() {
+			return nil
+		}
 
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				continue
+		// check if `f`, `a`, `l` in token stack and `s`, `e in mirror stack
+		itIsPartOfTokenFalse := func() bool {
+			left := []int{
+				TOKEN_ALPHABET_LOWERCASE_F,
+				TOKEN_ALPHABET_LOWERCASE_A,
+				TOKEN_ALPHABET_LOWERCASE_L,
 			}
-		case TOKEN_ALPHABET_UPPERCASE_E:
-
-			// as hex in unicode
-			if lexer.streamStoppedInAnStringUnicodeEscape() {
-				lexer.pushByteIntoPaddingContent(tokenSymbol)
-				// check if unicode escape is full length
-				if lexer.PaddingContent.Len() == 6 {
-					lexer.appendPaddingContentToJSONContent()
-					lexer.cleanPaddingContent()
-					// pop `\`, `u` from stack
-					lexer.popTokenStack()
-					lexer.popTokenStack()
-				}
-				continue
+			right := []int{
+				TOKEN_ALPHABET_LOWERCASE_E,
+				TOKEN_ALPHABET_LOWERCASE_S,
 			}
+			return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
+		}
+		if !itIsPartOfTokenFalse() {
+			return nil
+		}
+		lexer.pushTokenStack(token)
+		lexer.popMirrorTokenStack()
+	case TOKEN_ALPHABET_LOWERCASE_T:
 
-			// check if in a number, as `E` (exponent) in scientific notation
-			if lexer.streamStoppedInANumberDecimalPartMiddle() {
-				lexer.pushByteIntoPaddingContent(tokenSymbol)
-				continue
-			}
+		// \t escape `\`, `t`
+		if lexer.streamStoppedWithLeadingEscapeCharacter() {
+			// push padding escape character `\` into JSON content
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
 
 			// write current token symbol to JSON content
 			lexer.JSONContent.WriteByte(tokenSymbol)
 
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				continue
-			}
-		case TOKEN_NUMBER_0:
-			fallthrough
-		case TOKEN_NUMBER_1:
-			fallthrough
-		case TOKEN_NUMBER_2:
-			fallthrough
-		case TOKEN_NUMBER_3:
-			fallthrough
-		case TOKEN_NUMBER_4:
-			fallthrough
-		case TOKEN_NUMBER_5:
-			fallthrough
-		case TOKEN_NUMBER_6:
-			fallthrough
-		case TOKEN_NUMBER_7:
-			fallthrough
-		case TOKEN_NUMBER_8:
-			fallthrough
-		case TOKEN_NUMBER_9:
-
-			if lexer.streamStoppedInAnStringUnicodeEscape() {
-				lexer.pushByteIntoPaddingContent(tokenSymbol)
-				// check if unicode escape is full length
-				if lexer.PaddingContent.Len() == 6 {
-					lexer.appendPaddingContentToJSONContent()
-					lexer.cleanPaddingContent()
-					// pop `\`, `u` from stack
-					lexer.popTokenStack()
-					lexer.popTokenStack()
-				}
-				continue
-			}
+			// pop `\` from  stack
+			lexer.popTokenStack()
+			return nil
+		}
 
-			// check if json stream stopped with padding content, like `[1 , 1`
-			if lexer.havePaddingContent() {
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
-			}
+		// check if json stream stopped with padding content, like case `[true , t`
+		if lexer.havePaddingContent() {
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
+		}
 
-			// in negative part of a number
-			if lexer.streamStoppedInANegativeNumberValueStart() {
-				lexer.pushNegativeIntoJSONContent()
-				// pop `0` from mirror stack
-				lexer.popMirrorTokenStack()
-			}
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
 
-			// write current token symbol to JSON content
-			lexer.JSONContent.WriteByte(tokenSymbol)
+		// in a string, just skip token
+		if lexer.streamStoppedInAString() {
+			return nil
+		}
 
-			// in a string or a number, just skip token
-			if lexer.streamStoppedInAString() || lexer.streamStoppedInANumber() {
-				continue
-			}
+		// push `t` to stack
+		lexer.pushTokenStack(token)
+		if lexer.streamStoppedInAnArray() {
+			// in array
+			// push `r`, `u`, `e`
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_E)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_U)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_R)
+		} else {
+			// in object
+			// pop `n`, `u`, `l`, `l`
+			lexer.popMirrorTokenStack()
+			lexer.popMirrorTokenStack()
+			lexer.popMirrorTokenStack()
+			lexer.popMirrorTokenStack()
+			// push `r`, `u`, `e`
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_E)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_U)
+			lexer.pushMirrorTokenStack(TOKEN_ALPHABET_LOWERCASE_R)
+		}
+	case TOKEN_ALPHABET_LOWERCASE_U:
 
-			// in decimal part of a number
-			if lexer.streamStoppedInANumberDecimalPart() {
-				lexer.pushTokenStack(TOKEN_NUMBER)
-				// pop placeholder `0` in decimal part
-				lexer.popMirrorTokenStack()
-				continue
-			}
+		// unicode escape `\`, `u`
+		if lexer.streamStoppedWithLeadingEscapeCharacter() {
+			lexer.pushTokenStack(token)
+			lexer.PaddingContent.WriteByte(tokenSymbol)
+			return nil
+		}
 
-			// first number type token, push token into stack
-			lexer.pushTokenStack(TOKEN_NUMBER)
-
-			// check if we are in an object or an array
-			if lexer.streamStoppedInAnArray() {
-				continue
-			} else if lexer.streamStoppedInAnObjectNullValuePlaceholderStart() {
-				// pop `n`, `u`, `l`, `l`
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
+
+		// in a string, just skip token
+		if lexer.streamStoppedInAString() {
+			return nil
+		}
+
+		// check if `t`, `r` in token stack and, `u`, `e` in mirror stack
+		itIsPartOfTokenTrue := func() bool {
+			left := []int{
+				TOKEN_ALPHABET_LOWERCASE_T,
+				TOKEN_ALPHABET_LOWERCASE_R,
 			}
+			right := []int{
+				TOKEN_ALPHABET_LOWERCASE_E,
+				TOKEN_ALPHABET_LOWERCASE_U,
+			}
+			return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
+		}
 
-		case TOKEN_COMMA:
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				lexer.JSONContent.WriteByte(tokenSymbol)
-				continue
+		// check if `n` in token stack and `u`, `l`, `l` in mirror stack
+		itIsPartOfTokenNull := func() bool {
+			left := []int{
+				TOKEN_ALPHABET_LOWERCASE_N,
 			}
-			// in a object or a array, keep the comma in stack but not write it into JSONContent, until next token arrival
-			// the comma must following with token: quote, null, true, false, number
-			lexer.pushByteIntoPaddingContent(tokenSymbol)
-			lexer.pushTokenStack(token)
-		case TOKEN_DOT:
+			right := []int{
+				TOKEN_ALPHABET_LOWERCASE_L,
+				TOKEN_ALPHABET_LOWERCASE_L,
+				TOKEN_ALPHABET_LOWERCASE_U,
+			}
+			return matchStack(lexer.TokenStack, left) && matchStack(lexer.MirrorTokenStack, right)
+		}
+		if !itIsPartOfTokenTrue() && !itIsPartOfTokenNull() {
+			return nil
+		}
+		lexer.pushTokenStack(token)
+		lexer.popMirrorTokenStack()
+	case TOKEN_ALPHABET_UPPERCASE_A:
+		fallthrough
+	case TOKEN_ALPHABET_UPPERCASE_B:
+		fallthrough
+	case TOKEN_ALPHABET_UPPERCASE_C:
+		fallthrough
+	case TOKEN_ALPHABET_UPPERCASE_D:
+		fallthrough
+	case TOKEN_ALPHABET_LOWERCASE_C:
+		fallthrough
+	case TOKEN_ALPHABET_LOWERCASE_D:
+		fallthrough
+	case TOKEN_ALPHABET_UPPERCASE_F:
+
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
+
+		// in a string, just skip token
+		if lexer.streamStoppedInAString() {
+			return nil
+		}
+	case TOKEN_ALPHABET_UPPERCASE_E:
 
-			// write current token symbol to JSON content
+		// write current token symbol to JSON content
+		lexer.JSONContent.WriteByte(tokenSymbol)
+
+		// in a string, just skip token
+		if lexer.streamStoppedInAString() {
+			return nil
+		}
+	case TOKEN_NUMBER_0:
+		fallthrough
+	case TOKEN_NUMBER_1:
+		fallthrough
+	case TOKEN_NUMBER_2:
+		fallthrough
+	case TOKEN_NUMBER_3:
+		fallthrough
+	case TOKEN_NUMBER_4:
+		fallthrough
+	case TOKEN_NUMBER_5:
+		fallthrough
+	case TOKEN_NUMBER_6:
+		fallthrough
+	case TOKEN_NUMBER_7:
+		fallthrough
+	case TOKEN_NUMBER_8:
+		fallthrough
+	case TOKEN_NUMBER_9:
+
+		// check if json stream stopped with padding content, like `[1 , 1`
+		if lexer.havePaddingContent() {
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
+		}
+
+		// in a string or after a number, just skip token
+		if lexer.streamStoppedInAString() || lexer.streamStoppedInANumber() {
 			lexer.JSONContent.WriteByte(tokenSymbol)
+			return nil
+		}
 
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				continue
-			}
+		// first number type token
+		lexer.startNumber(tokenSymbol)
 
-			// use 0 for decimal part place holder
-			lexer.pushTokenStack(token)
-			lexer.pushMirrorTokenStack(TOKEN_NUMBER_0)
-		case TOKEN_SLASH:
-
-			// escape character `\`, `/`
-			if lexer.streamStoppedWithLeadingEscapeCharacter() {
-				// push padding escape character `\` into JSON content
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
-
-				// write current token symbol to JSON content
-				lexer.JSONContent.WriteByte(tokenSymbol)
-
-				// pop `\` from  stack
-				lexer.popTokenStack()
-				continue
-			} else if lexer.streamStoppedInAString() {
-				// in a string, and the preceding token isn't an escape character, write current token symbol to JSON content
-				lexer.JSONContent.WriteByte(tokenSymbol)
-				continue
-			}
+	case TOKEN_COMMA:
+		// in a string, just skip token
+		if lexer.streamStoppedInAString() {
+			lexer.JSONContent.WriteByte(tokenSymbol)
+			return nil
+		}
+		// in a object or a array, keep the comma in stack but not write it into JSONContent, until next token arrival
+		// the comma must following with token: quote, null, true, false, number
+		lexer.pushByteIntoPaddingContent(tokenSymbol)
+		// the previous member finished, pop its tokens from stack
+		lexer.popMemberFromTokenStack()
+		lexer.pushTokenStack(token)
+	case TOKEN_DOT:
+
+		// write current token symbol to JSON content, the decimal point of a number is lexed by the number lexer
+		lexer.JSONContent.WriteByte(tokenSymbol)
+	case TOKEN_SLASH:
+
+		// escape character `\`, `/`
+		if lexer.streamStoppedWithLeadingEscapeCharacter() {
+			// push padding escape character `\` into JSON content
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
 
-		case TOKEN_ESCAPE_CHARACTER:
+			// write current token symbol to JSON content
+			lexer.JSONContent.WriteByte(tokenSymbol)
 
-			// double escape character `\`, `\`
-			if lexer.streamStoppedWithLeadingEscapeCharacter() {
-				// push padding escape character `\` into JSON content
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
+			// pop `\` from  stack
+			lexer.popTokenStack()
+			return nil
+		} else if lexer.streamStoppedInAString() {
+			// in a string, and the preceding token isn't an escape character, write current token symbol to JSON content
+			lexer.JSONContent.WriteByte(tokenSymbol)
+			return nil
+		}
 
-				// write current token symbol to JSON content
-				lexer.JSONContent.WriteByte(tokenSymbol)
+	case TOKEN_ESCAPE_CHARACTER:
 
-				// pop `\` from  stack
-				lexer.popTokenStack()
-				continue
-			}
+		// double escape character `\`, `\`
+		if lexer.streamStoppedWithLeadingEscapeCharacter() {
+			// push padding escape character `\` into JSON content
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
 
-			// just write escape character into stack and waitting other token trigger escape method.
-			lexer.pushTokenStack(token)
-			lexer.pushByteIntoPaddingContent(TOKEN_ESCAPE_CHARACTER_SYMBOL)
-		case TOKEN_NEGATIVE:
+			// write current token symbol to JSON content
+			lexer.JSONContent.WriteByte(tokenSymbol)
 
-			// in a string, just skip token
-			if lexer.streamStoppedInAString() {
-				lexer.JSONContent.WriteByte(tokenSymbol)
-				continue
-			}
+			// pop `\` from  stack
+			lexer.popTokenStack()
+			return nil
+		}
 
-			// check if json stream stopped with padding content, like `[1 , -`
-			if lexer.havePaddingContent() {
-				lexer.appendPaddingContentToJSONContent()
-				lexer.cleanPaddingContent()
-			}
+		// just write escape character into stack and waitting other token trigger escape method.
+		lexer.pushTokenStack(token)
+		lexer.pushByteIntoPaddingContent(TOKEN_ESCAPE_CHARACTER_SYMBOL)
+	case TOKEN_NEGATIVE:
 
-			// just write negative character into stack and waitting other token trigger it.
-			lexer.pushTokenStack(token)
-			if lexer.streamStoppedInAnObjectNegativeNumberValueStart() {
-				// pop `n`, `u`, `l`, `l` from mirror stack
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-				lexer.popMirrorTokenStack()
-			}
+		// in a string, just skip token
+		if lexer.streamStoppedInAString() {
+			lexer.JSONContent.WriteByte(tokenSymbol)
+			return nil
+		}
 
-			// push `0` into mirror stack for placeholder
-			lexer.pushMirrorTokenStack(TOKEN_NUMBER_0)
-		default:
-			return fmt.Errorf("unexpected token: `%d`, token symbol: `%c`", token, tokenSymbol)
+		// check if json stream stopped with padding content, like `[1 , -`
+		if lexer.havePaddingContent() {
+			lexer.appendPaddingContentToJSONContent()
+			lexer.cleanPaddingContent()
 		}
 
-		// check if end
-		if token == TOKEN_EOF {
-			break
+		// after a number, just skip token
+		if lexer.streamStoppedInANumber() {
+			lexer.JSONContent.WriteByte(tokenSymbol)
+			return nil
 		}
+
+		// negative number starts
+		lexer.startNumber(tokenSymbol)
+	default:
+		return lexer.newSyntaxError(fmt.Sprintf("unexpected token: `%d`, token symbol: `%c`", token, tokenSymbol), position, tokenSymbol)
 	}
 	return nil
 }
@@ -1162,7 +1403,48 @@ func (lexer *Lexer) CompleteJSON() string {
 	return lexer.completeJSON()
 }
 
+// complete the incomplete JSON string and append it to dst, returns the extended buffer
+// reuse dst between calls to complete the JSON without allocation
+func (lexer *Lexer) CompleteJSONTo(dst []byte) []byte {
+	content, mirrorTokens := lexer.completionParts()
+	dst = append(dst, content...)
+	return append(dst, mirrorTokens...)
+}
+
+// complete the incomplete JSON string incrementally, prev must be the result of the previous call, or nil for the first call.
+// the stable prefix in prev is kept, only the bytes stable since the previous call and the volatile tail are appended,
+// so the work of each call is independent of the length of JSON content. it starts over for each document
+// in multiple documents mode, and if prev is shorter than the stable prefix kept
+func (lexer *Lexer) CompleteJSONIncremental(prev []byte) []byte {
+	end := lexer.stableEnd()
+	kept := lexer.completedStable
+	if kept > len(prev) || kept > end {
+		kept = 0
+	}
+	content, mirrorTokens := lexer.completionParts()
+	completedJSON := append(prev[:kept], content[kept:]...)
+	lexer.completedStable = end
+	return append(completedJSON, mirrorTokens...)
+}
+
+// write the completed JSON to w, the JSON content is written without copying
+func (lexer *Lexer) WriteCompletedTo(w io.Writer) (int64, error) {
+	content, mirrorTokens := lexer.completionParts()
+	n, err := w.Write(content)
+	written := int64(n)
+	if err != nil {
+		return written, err
+	}
+	n, err = w.Write(mirrorTokens)
+	return written + int64(n), err
+}
+
 // complete the incomplete JSON string by concat JSON content and mirror tokens
 func (lexer *Lexer) completeJSON() string {
-	return lexer.JSONContent.String() + lexer.dumpMirrorTokenStackToString()
+	content, mirrorTokens := lexer.completionParts()
+	var completedJSON strings.Builder
+	completedJSON.Grow(len(content) + len(mirrorTokens))
+	completedJSON.Write(content)
+	completedJSON.Write(mirrorTokens)
+	return completedJSON.String()
 }
@@ -0,0 +1,69 @@
+package streamingjsongo
+
+import (
+	"fmt"
+)
+
+// atomic value at a JSON Pointer
+type atomicPath struct {
+	tokens  []string         // reference tokens of JSON Pointer
+	indexes []int            // array indexes of reference tokens, -1 if the token is not an array index
+	policy  CompletionPolicy // CompleteNull or CompleteOmit
+}
+
+// check if the policy can hide atomic values
+func isAtomicPolicy(policy CompletionPolicy) bool {
+	return policy == CompleteNull || policy == CompleteOmit
+}
+
+// get the atomic policy of the string or number value in lexing, returns CompleteKeep if it is not atomic
+func (lexer *Lexer) openAtomicPolicy() CompletionPolicy {
+	g := &lexer.grammar
+	if !g.inNumber() && !(g.inString() && !g.inKey) {
+		return CompleteKeep
+	}
+	if lexer.value != nil {
+		return lexer.value.scalarPolicy
+	}
+	// the value builder is always attached for atomic paths
+	return lexer.options.atomicPolicy
+}
+
+// check if the value of the object member in lexing is not started and may be omitted as an atomic value
+func (lexer *Lexer) memberValueMayBeOmitted() bool {
+	g := &lexer.grammar
+	if g.topContainer() != TOKEN_LEFT_BRACE {
+		return false
+	}
+	if !(g.inString() && g.inKey) && g.state != grammarStateObjectColon && g.state != grammarStateValue {
+		return false
+	}
+	if lexer.options.atomicPolicy == CompleteOmit {
+		return true
+	}
+	for i := range lexer.atomicPaths {
+		if lexer.atomicPaths[i].policy == CompleteOmit {
+			return true
+		}
+	}
+	return false
+}
+
+// make the string or number value at given JSON Pointer (RFC 6901) atomic, like `/url` or `/items/0/id`,
+// the partial value is hidden from the completed JSON and the partial value until its closing quote or delimiter arrived,
+// it is replaced by `null` with CompleteNull, or omitted with CompleteOmit. call it before appending the JSON stream
+func (lexer *Lexer) SetAtomic(pointer string, policy CompletionPolicy) error {
+	if !isAtomicPolicy(policy) {
+		return fmt.Errorf("invalid atomic policy %d, it must be CompleteNull or CompleteOmit", policy)
+	}
+	tokens, indexes, err := parseJSONPointerPath(pointer)
+	if err != nil {
+		return err
+	}
+	lexer.atomicPaths = append(lexer.atomicPaths, atomicPath{tokens: tokens, indexes: indexes, policy: policy})
+	if lexer.value == nil {
+		return lexer.attachValueBuilder()
+	}
+	lexer.value.atomicPaths = lexer.atomicPaths
+	return nil
+}
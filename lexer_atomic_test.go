@@ -0,0 +1,74 @@
+package streamingjsongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAtomicValues(t *testing.T) {
+	// the value is the completed JSON by CompleteNull and CompleteOmit
+	streamingJSONCase := map[string][2]string{
+		`{"url":"https://exa`:      {`{"url":null}`, `{}`},
+		`{"id":1,"n":12`:           {`{"id":1,"n":null}`, `{"id":1}`},
+		`{"id":1,"n":-`:            {`{"id":1,"n":null}`, `{"id":1}`},
+		`{"id":1,"n":12,`:          {`{"id":1,"n":12}`, `{"id":1,"n":12}`},
+		`{"id":1,"n":12 `:          {`{"id":1,"n":12}`, `{"id":1,"n":12}`},
+		`{"a":"x","b":tr`:          {`{"a":"x","b":true}`, `{"a":"x","b":true}`},
+		`{"a":"x","b`:              {`{"a":"x","b":null}`, `{"a":"x","b":null}`},
+		`["a", "b`:                 {`["a", null]`, `["a"]`},
+		`[[1, 2`:                   {`[[1, null]]`, `[[1]]`},
+		`"partial`:                 {`null`, `null`},
+		`12`:                       {`null`, `null`},
+		`{"url":"https://example"`: {`{"url":"https://example"}`, `{"url":"https://example"}`},
+		`{"a":1,"a":-`:             {`{"a":1,"a":null}`, `{"a":1}`},
+		`{"a":"x","b":2,"a":"y`:    {`{"a":"x","b":2,"a":null}`, `{"a":"x","b":2}`},
+	}
+	for testCase, expect := range streamingJSONCase {
+		for i, policy := range []CompletionPolicy{CompleteNull, CompleteOmit} {
+			lexer := NewLexer(WithAtomicValues(policy))
+			assert.Nil(t, lexer.AppendString(testCase))
+			assert.Equal(t, expect[i], lexer.CompleteJSON(), "unexpected completion in case: %s", testCase)
+			assert.Equal(t, expect[i], lexer.CompleteJSONWith(CompletionOptions{}), "unexpected completion in case: %s", testCase)
+			assert.Equal(t, expect[i], string(lexer.StablePrefix())+string(lexer.VolatileTail()), "unexpected stable prefix in case: %s", testCase)
+			value, err := lexer.Value()
+			assert.Nil(t, err)
+			assert.Equal(t, decodeCompletedJSON(t, expect[i]), value, "unexpected value in case: %s", testCase)
+		}
+	}
+}
+
+func TestSetAtomic(t *testing.T) {
+	streamingJSONContent := `{"title": "weather", "items": [{"id": "a-1", "temp": -1.5e1}, {"id": "b-2", "temp": 20}], "url": "https://example.com", "n": 1234}`
+	for _, policy := range []CompletionPolicy{CompleteNull, CompleteOmit} {
+		lexer := NewLexer()
+		assert.Nil(t, lexer.SetAtomic("/url", policy))
+		assert.Nil(t, lexer.SetAtomic("/n", policy))
+		assert.Nil(t, lexer.SetAtomic("/items/1/id", policy))
+		for i := 0; i < len(streamingJSONContent); i++ {
+			assert.Nil(t, lexer.AppendString(streamingJSONContent[i:i+1]))
+			completedJSON := lexer.CompleteJSON()
+			value, err := lexer.Value()
+			assert.Nil(t, err)
+			if !assert.Equal(t, decodeCompletedJSON(t, completedJSON), value, "unexpected value at: %s", streamingJSONContent[:i+1]) {
+				break
+			}
+			assert.Equal(t, completedJSON, string(lexer.StablePrefix())+string(lexer.VolatileTail()))
+		}
+	}
+
+	lexer := NewLexer()
+	assert.Nil(t, lexer.SetAtomic("/items/1/id", CompleteOmit))
+	assert.Nil(t, lexer.AppendString(`{"items": [{"id": "a-`))
+	assert.Equal(t, `{"items": [{"id": "a-"}]}`, lexer.CompleteJSON())
+	assert.Nil(t, lexer.AppendString(`1"}, {"id": "b-`))
+	assert.Equal(t, `{"items": [{"id": "a-1"}, {}]}`, lexer.CompleteJSON())
+
+	// atomic paths are kept by Reset()
+	lexer.Reset()
+	assert.Nil(t, lexer.AppendString(`{"items": [{}, {"id": "b-`))
+	assert.Equal(t, `{"items": [{}, {}]}`, lexer.CompleteJSON())
+
+	assert.NotNil(t, lexer.SetAtomic("url", CompleteNull))
+	assert.NotNil(t, lexer.SetAtomic("/url", CompleteKeep))
+}
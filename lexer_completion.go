@@ -0,0 +1,189 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// policy of completing an incomplete part of JSON stream
+type CompletionPolicy int
+
+const (
+	CompleteKeep     CompletionPolicy = iota // complete it as CompleteJSON() does, like `tr` to `true` and `{"a":` to `{"a":null}`
+	CompleteNull                             // replace the value by `null`
+	CompleteOmit                             // omit the object member or array element, the top-level value is replaced by `null`
+	CompleteSentinel                         // replace the value by CompletionOptions.Sentinel
+	CompleteZero                             // replace the value by the zero value of its type, like `""`, `0` and `false`, or `null` if the type is unknown
+)
+
+// options of CompleteJSONWith(), the zero value completes the JSON as CompleteJSON() does
+type CompletionOptions struct {
+	IncompleteKey  CompletionPolicy // incomplete keys like `{"a`, CompleteOmit omits the member, other policies close the key and complete its value by MissingValue
+	MissingValue   CompletionPolicy // missing values of object members like `{"a":`, CompleteKeep and CompleteZero are the same as CompleteNull
+	PartialString  CompletionPolicy // partial strings like `{"a":"b`
+	PartialNumber  CompletionPolicy // partial numbers like `{"a":-1.`
+	PartialLiteral CompletionPolicy // partial literals like `{"a":tr`
+	Sentinel       string           // JSON of the sentinel value for CompleteSentinel policies, like `"<pending>"`, `null` if it is invalid
+}
+
+// completion of the innermost incomplete part
+type completion struct {
+	cut         int    // the JSON content is kept to the cut offset
+	replacement string // completed JSON after the kept content, before closing tokens of open containers
+}
+
+// omit the member or element in lexing off the innermost container, or replace the top-level value by null
+func (lexer *Lexer) omitMember() completion {
+	memberCutsLen := len(lexer.grammar.memberCuts)
+	if memberCutsLen == 0 {
+		return completion{cut: 0, replacement: "null"}
+	}
+	return completion{cut: lexer.grammar.memberCuts[memberCutsLen-1], replacement: ""}
+}
+
+// complete a missing value by policy, prefix is the completed JSON before it like `:`
+func (lexer *Lexer) completeMissingValue(policy CompletionPolicy, prefix string, opts *CompletionOptions) completion {
+	switch policy {
+	case CompleteOmit:
+		return lexer.omitMember()
+	case CompleteSentinel:
+		return completion{cut: lexer.JSONContent.Len(), replacement: prefix + opts.Sentinel}
+	}
+	return completion{cut: lexer.JSONContent.Len(), replacement: prefix + "null"}
+}
+
+// complete a partial value started at the scalar start by policy, completed is the JSON replacing it for CompleteKeep,
+// and zero is the zero value of its type for CompleteZero
+func (lexer *Lexer) completePartialValue(policy CompletionPolicy, completed string, zero string, opts *CompletionOptions) completion {
+	switch policy {
+	case CompleteZero:
+		return completion{cut: lexer.grammar.scalarStart, replacement: zero}
+	case CompleteNull:
+		return completion{cut: lexer.grammar.scalarStart, replacement: "null"}
+	case CompleteOmit:
+		return lexer.omitMember()
+	case CompleteSentinel:
+		return completion{cut: lexer.grammar.scalarStart, replacement: opts.Sentinel}
+	}
+	return completion{cut: lexer.grammar.scalarStart, replacement: completed}
+}
+
+// complete the innermost incomplete part of JSON stream by options
+func (lexer *Lexer) completeInnermost(opts *CompletionOptions) completion {
+	g := &lexer.grammar
+	content := lexer.JSONContent.Bytes()
+	if held, ok := lexer.heldValue(); ok {
+		// the number or literal held by the stages before lexing is completed by the same policies
+		if held.number {
+			return lexer.completeHeldValue(opts.PartialNumber, held, opts)
+		}
+		return lexer.completeHeldValue(opts.PartialLiteral, held, opts)
+	}
+	switch {
+	case g.inString():
+		if !g.inKey {
+			return lexer.completePartialValue(opts.PartialString, string(content[g.scalarStart:])+`"`, `""`, opts)
+		}
+		if opts.IncompleteKey == CompleteOmit {
+			return lexer.omitMember()
+		}
+		return lexer.completeMissingValue(opts.MissingValue, `":`, opts)
+	case g.inNumber():
+		return lexer.completePartialValue(opts.PartialNumber, string(appendCompletedNumber(nil, content[g.scalarStart:])), "0", opts)
+	}
+	switch g.state {
+	case grammarStateLiteral:
+		// the zero value of `t` and `f` is `false`, the type of `n` is unknown
+		zero := tokenSymbolMap[TOKEN_FLASE]
+		if g.literal == tokenSymbolMap[TOKEN_NULL] {
+			zero = g.literal
+		}
+		return lexer.completePartialValue(opts.PartialLiteral, g.literal, zero, opts)
+	case grammarStateObjectColon:
+		return lexer.completeMissingValue(opts.MissingValue, `:`, opts)
+	case grammarStateValue:
+		if g.topContainer() == TOKEN_LEFT_BRACE {
+			return lexer.completeMissingValue(opts.MissingValue, ``, opts)
+		}
+	}
+	return completion{cut: len(content)}
+}
+
+// complete the incomplete JSON string by options, the zero value of options completes it as CompleteJSON() does.
+// incomplete keys are always omitted with WithOmitDanglingKeys(), and a Sentinel which is not a valid JSON value
+// is replaced by `null`. it falls back to CompleteJSON() if the stream can never be a valid JSON in lenient mode
+func (lexer *Lexer) CompleteJSONWith(opts CompletionOptions) string {
+	if lexer.grammarError != nil {
+		return lexer.CompleteJSON()
+	}
+	if lexer.options.omitDanglingKeys {
+		opts.IncompleteKey = CompleteOmit
+	}
+	if policy := lexer.openAtomicPolicy(); policy != CompleteKeep {
+		opts.PartialString = policy
+		opts.PartialNumber = policy
+	}
+	if held, ok := lexer.heldValue(); ok {
+		if policy := lexer.heldAtomicPolicy(held); policy != CompleteKeep {
+			opts.PartialNumber = policy
+		}
+	}
+	if !json.Valid([]byte(opts.Sentinel)) {
+		opts.Sentinel = tokenSymbolMap[TOKEN_NULL]
+	}
+	innermost := lexer.completeInnermost(&opts)
+	var completedJSON strings.Builder
+	completedJSON.Grow(innermost.cut + len(innermost.replacement) + len(lexer.grammar.containers))
+	completedJSON.Write(lexer.JSONContent.Bytes()[:innermost.cut])
+	completedJSON.WriteString(innermost.replacement)
+	completedJSON.Write(lexer.appendClosingTokens(nil))
+	return completedJSON.String()
+}
+
+// append closing tokens of open containers to dst, innermost first
+func (lexer *Lexer) appendClosingTokens(dst []byte) []byte {
+	containers := lexer.grammar.containers
+	for i := len(containers) - 1; i >= 0; i-- {
+		if containers[i] == TOKEN_LEFT_BRACE {
+			dst = append(dst, TOKEN_RIGHT_BRACE_SYMBOL)
+		} else {
+			dst = append(dst, TOKEN_RIGHT_BRACKET_SYMBOL)
+		}
+	}
+	return dst
+}
+
+// get the completion hiding the dangling key by WithOmitDanglingKeys() or the atomic value in lexing,
+// or completing the value held by the stages before lexing, returns false if nothing is hidden
+func (lexer *Lexer) hiddenCompletion() (completion, bool) {
+	if lexer.grammarError != nil {
+		return completion{}, false
+	}
+	if held, ok := lexer.heldValue(); ok {
+		return lexer.completeHeldValue(lexer.heldAtomicPolicy(held), held, nil), true
+	}
+	if lexer.options.omitDanglingKeys && lexer.grammar.inString() && lexer.grammar.inKey {
+		return lexer.omitMember(), true
+	}
+	if policy := lexer.openAtomicPolicy(); policy != CompleteKeep {
+		return lexer.completePartialValue(policy, "", "", nil), true
+	}
+	return completion{}, false
+}
+
+// get the JSON content and mirror tokens forming the completed JSON, the mirror tokens are owned by the lexer
+func (lexer *Lexer) completionParts() ([]byte, []byte) {
+	content := lexer.JSONContent.Bytes()
+	if hidden, ok := lexer.hiddenCompletion(); ok {
+		lexer.completionTail = append(lexer.completionTail[:0], hidden.replacement...)
+		lexer.completionTail = lexer.appendClosingTokens(lexer.completionTail)
+		return content[:hidden.cut], lexer.completionTail
+	}
+	if lexer.number.open() {
+		// the partial number is completed by the number lexer, like `-` to `0`
+		lexer.completionTail = appendCompletedNumber(lexer.completionTail[:0], content[lexer.number.start:])
+		lexer.completionTail = append(lexer.completionTail, lexer.dumpMirrorTokenStack()...)
+		return content[:lexer.number.start], lexer.completionTail
+	}
+	return content, lexer.dumpMirrorTokenStack()
+}
@@ -0,0 +1,171 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompleteJSONWith_default(t *testing.T) {
+	streamingJSONContent := `{"string": "这是一个字符串", "integer": 42, "float": 3.14159, "exponent": -1.5E-3, "boolean_true": true, "boolean_false": false, "null": null, "object": {"empty_object": {}, "escape": "\"\\中", "array":["string in array", -123, 45.67e-3, false, {"k": "v"}, []]}}`
+	lexer := NewLexer()
+	for i := 0; i < len(streamingJSONContent); i++ {
+		assert.Nil(t, lexer.AppendString(streamingJSONContent[i:i+1]))
+		assert.Equal(t, lexer.CompleteJSON(), lexer.CompleteJSONWith(CompletionOptions{}), "unexpected completion at: %s", streamingJSONContent[:i+1])
+	}
+}
+
+func TestCompleteJSONWith(t *testing.T) {
+	omit := CompletionOptions{IncompleteKey: CompleteOmit, MissingValue: CompleteOmit, PartialString: CompleteOmit, PartialNumber: CompleteOmit, PartialLiteral: CompleteOmit}
+	null := CompletionOptions{MissingValue: CompleteNull, PartialString: CompleteNull, PartialNumber: CompleteNull, PartialLiteral: CompleteNull}
+	sentinel := CompletionOptions{MissingValue: CompleteSentinel, PartialString: CompleteSentinel, PartialNumber: CompleteSentinel, PartialLiteral: CompleteSentinel, Sentinel: `"<pending>"`}
+	zero := CompletionOptions{IncompleteKey: CompleteZero, MissingValue: CompleteZero, PartialString: CompleteZero, PartialNumber: CompleteZero, PartialLiteral: CompleteZero}
+	// the value is the completed JSON by omit, null, sentinel and zero options
+	streamingJSONCase := map[string][4]string{
+		`{"a":1,"b`:          {`{"a":1}`, `{"a":1,"b":null}`, `{"a":1,"b":"<pending>"}`, `{"a":1,"b":null}`},
+		`{"a":1,"b"`:         {`{"a":1}`, `{"a":1,"b":null}`, `{"a":1,"b":"<pending>"}`, `{"a":1,"b":null}`},
+		`{"a":1, "b" :`:      {`{"a":1}`, `{"a":1, "b" :null}`, `{"a":1, "b" :"<pending>"}`, `{"a":1, "b" :null}`},
+		`{"a":1,"b":"x`:      {`{"a":1}`, `{"a":1,"b":null}`, `{"a":1,"b":"<pending>"}`, `{"a":1,"b":""}`},
+		`{"a":1,"b":"x\u00`:  {`{"a":1}`, `{"a":1,"b":null}`, `{"a":1,"b":"<pending>"}`, `{"a":1,"b":""}`},
+		`{"a":1,"b":-1.`:     {`{"a":1}`, `{"a":1,"b":null}`, `{"a":1,"b":"<pending>"}`, `{"a":1,"b":0}`},
+		`{"a":1,"b":tr`:      {`{"a":1}`, `{"a":1,"b":null}`, `{"a":1,"b":"<pending>"}`, `{"a":1,"b":false}`},
+		`{"a":1,"b":f`:       {`{"a":1}`, `{"a":1,"b":null}`, `{"a":1,"b":"<pending>"}`, `{"a":1,"b":false}`},
+		`{"a":1,"b":nu`:      {`{"a":1}`, `{"a":1,"b":null}`, `{"a":1,"b":"<pending>"}`, `{"a":1,"b":null}`},
+		`{"a":1,"b":12 `:     {`{"a":1,"b":12}`, `{"a":1,"b":12}`, `{"a":1,"b":12}`, `{"a":1,"b":12}`},
+		`{"a":1,`:            {`{"a":1}`, `{"a":1}`, `{"a":1}`, `{"a":1}`},
+		`{"a":"x`:            {`{}`, `{"a":null}`, `{"a":"<pending>"}`, `{"a":""}`},
+		`[1, "x`:             {`[1]`, `[1, null]`, `[1, "<pending>"]`, `[1, ""]`},
+		`[1, 2`:              {`[1]`, `[1, null]`, `[1, "<pending>"]`, `[1, 0]`},
+		`[1, [2, fal`:        {`[1, [2]]`, `[1, [2, null]]`, `[1, [2, "<pending>"]]`, `[1, [2, false]]`},
+		`[1, {"a":[2], "b":`: {`[1, {"a":[2]}]`, `[1, {"a":[2], "b":null}]`, `[1, {"a":[2], "b":"<pending>"}]`, `[1, {"a":[2], "b":null}]`},
+		`"x`:                 {`null`, `null`, `"<pending>"`, `""`},
+		`-1`:                 {`null`, `null`, `"<pending>"`, `0`},
+		`{`:                  {`{}`, `{}`, `{}`, `{}`},
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer()
+		assert.Nil(t, lexer.AppendString(testCase))
+		assert.Equal(t, expect[0], lexer.CompleteJSONWith(omit), "unexpected omit completion in case: %s", testCase)
+		assert.Equal(t, expect[1], lexer.CompleteJSONWith(null), "unexpected null completion in case: %s", testCase)
+		assert.Equal(t, expect[2], lexer.CompleteJSONWith(sentinel), "unexpected sentinel completion in case: %s", testCase)
+		assert.Equal(t, expect[3], lexer.CompleteJSONWith(zero), "unexpected zero completion in case: %s", testCase)
+	}
+}
+
+func TestCompleteJSONWith_valid(t *testing.T) {
+	policies := []CompletionPolicy{CompleteKeep, CompleteNull, CompleteOmit, CompleteSentinel, CompleteZero}
+	var combinations []CompletionOptions
+	for _, incompleteKey := range policies {
+		for _, missingValue := range policies {
+			for _, partialString := range policies {
+				for _, partialNumber := range policies {
+					for _, partialLiteral := range policies {
+						combinations = append(combinations, CompletionOptions{
+							IncompleteKey:  incompleteKey,
+							MissingValue:   missingValue,
+							PartialString:  partialString,
+							PartialNumber:  partialNumber,
+							PartialLiteral: partialLiteral,
+							Sentinel:       `"<pending>"`,
+						})
+					}
+				}
+			}
+		}
+	}
+	streamingJSONContent := `{"s": "x\u00e9", "n": [-1.5e+3, 0], "t": true, "f": [false, null], "o": {"k": "v"}}`
+	lexer := NewLexer()
+	for i := 0; i < len(streamingJSONContent); i++ {
+		assert.Nil(t, lexer.AppendString(streamingJSONContent[i:i+1]))
+		for _, opts := range combinations {
+			completedJSON := lexer.CompleteJSONWith(opts)
+			if !assert.True(t, json.Valid([]byte(completedJSON)), "invalid completion %s by %+v at: %s", completedJSON, opts, streamingJSONContent[:i+1]) {
+				return
+			}
+		}
+	}
+}
+
+func TestCompleteJSONWith_mixed(t *testing.T) {
+	lexer := NewLexer()
+	assert.Nil(t, lexer.AppendString(`{"a":"x`))
+	assert.Equal(t, `{"a":"x"}`, lexer.CompleteJSONWith(CompletionOptions{PartialNumber: CompleteOmit}))
+	lexer.Reset()
+	assert.Nil(t, lexer.AppendString(`{"a`))
+	assert.Equal(t, `{"a":"-"}`, lexer.CompleteJSONWith(CompletionOptions{MissingValue: CompleteSentinel, Sentinel: `"-"`}))
+	assert.Equal(t, `{}`, lexer.CompleteJSONWith(CompletionOptions{IncompleteKey: CompleteOmit, MissingValue: CompleteSentinel, Sentinel: `"-"`}))
+	lexer.Reset()
+	assert.Nil(t, lexer.AppendString(`[1.5e+`))
+	assert.Equal(t, `[1.5e0]`, lexer.CompleteJSONWith(CompletionOptions{}))
+}
+
+func TestCompleteJSONWith_invalidSentinel(t *testing.T) {
+	lexer := NewLexer()
+	assert.Nil(t, lexer.AppendString(`{"a":"x`))
+	for _, sentinel := range []string{``, `<pending>`, `"x`, `1 2`, `]`} {
+		completedJSON := lexer.CompleteJSONWith(CompletionOptions{PartialString: CompleteSentinel, Sentinel: sentinel})
+		assert.Equal(t, `{"a":null}`, completedJSON, "unexpected completion of sentinel: %s", sentinel)
+	}
+	assert.Equal(t, `{"a":"-"}`, lexer.CompleteJSONWith(CompletionOptions{PartialString: CompleteSentinel, Sentinel: `"-"`}))
+}
+
+func TestCompleteJSONWith_held(t *testing.T) {
+	// the values held by the relaxed and Python stages are completed by the same policies
+	streamingJSONCase := []struct {
+		options []Option
+		stream  string
+		opts    CompletionOptions
+		expect  string
+	}{
+		{[]Option{WithPythonLiterals()}, `{"a": Tr`, CompletionOptions{PartialLiteral: CompleteNull}, `{"a": null}`},
+		{[]Option{WithPythonLiterals()}, `{"a": Tr`, CompletionOptions{PartialLiteral: CompleteZero}, `{"a": false}`},
+		{[]Option{WithPythonLiterals()}, `{"a": 1, "b": No`, CompletionOptions{PartialLiteral: CompleteOmit}, `{"a": 1}`},
+		{[]Option{WithPythonLiterals()}, `[1, -`, CompletionOptions{PartialNumber: CompleteSentinel, Sentinel: `"?"`}, `[1, "?"]`},
+		{[]Option{WithRelaxed()}, `{a: 0x1F`, CompletionOptions{PartialNumber: CompleteNull}, `{"a": null}`},
+		{[]Option{WithRelaxed()}, `[-0`, CompletionOptions{PartialNumber: CompleteOmit}, `[]`},
+		{[]Option{WithRelaxed()}, `[Infin`, CompletionOptions{PartialLiteral: CompleteZero}, `[null]`},
+		{[]Option{WithRelaxed(), WithAtomicValues(CompleteNull)}, `{a: 0x1`, CompletionOptions{}, `{"a": null}`},
+	}
+	for _, testCase := range streamingJSONCase {
+		lexer := NewLexer(testCase.options...)
+		assert.Nil(t, lexer.AppendString(testCase.stream))
+		assert.Equal(t, testCase.expect, lexer.CompleteJSONWith(testCase.opts), "unexpected completion in case: %s", testCase.stream)
+	}
+}
+
+func TestWithOmitDanglingKeys(t *testing.T) {
+	streamingJSONCase := map[string]string{
+		`{"a":1,"b`:            `{"a":1}`,
+		`{"a":1 , "b`:          `{"a":1}`,
+		`{"a":1,"b"`:           `{"a":1,"b":null}`,
+		`{"a":1,"b":`:          `{"a":1,"b":null}`,
+		`{"a`:                  `{}`,
+		`{"a":{"b":[{"c\u00`:   `{"a":{"b":[{}]}}`,
+		`[{"a":"x"},{"b\"`:     `[{"a":"x"},{}]`,
+		`{"a":"in \"key\" out`: `{"a":"in \"key\" out"}`,
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer(WithOmitDanglingKeys())
+		assert.Nil(t, lexer.AppendString(testCase))
+		assert.Equal(t, expect, lexer.CompleteJSON(), "unexpected completion in case: %s", testCase)
+		assert.Equal(t, expect, string(lexer.CompleteJSONTo(nil)), "unexpected completion in case: %s", testCase)
+		assert.Equal(t, expect, lexer.CompleteJSONWith(CompletionOptions{}), "unexpected completion in case: %s", testCase)
+		assert.Equal(t, expect, string(lexer.StablePrefix())+string(lexer.VolatileTail()), "unexpected stable prefix in case: %s", testCase)
+	}
+}
+
+func TestWithOmitDanglingKeys_streaming(t *testing.T) {
+	streamingJSONContent := `{"title": "weather", "items": [{"city": "Paris", "temp": -1.5e1}, {"city": "Lon", "tags": ["a", "b"]}], "ok": true, "a/b": null}`
+	lexer := NewLexer(WithOmitDanglingKeys())
+	for i := 0; i < len(streamingJSONContent); i++ {
+		assert.Nil(t, lexer.AppendString(streamingJSONContent[i:i+1]))
+		completedJSON := lexer.CompleteJSON()
+		value, err := lexer.Value()
+		assert.Nil(t, err)
+		if !assert.Equal(t, decodeCompletedJSON(t, completedJSON), value, "unexpected value at: %s", streamingJSONContent[:i+1]) {
+			break
+		}
+		assert.Equal(t, completedJSON, string(lexer.StablePrefix())+string(lexer.VolatileTail()))
+	}
+}
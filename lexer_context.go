@@ -0,0 +1,138 @@
+package streamingjsongo
+
+import (
+	"strconv"
+	"strings"
+)
+
+// syntactic state of JSON stream reported by Context()
+type StreamState int
+
+const (
+	StateBetweenValues StreamState = iota // between values, like `{"a":1` or `{"a":1,`, or before the top-level value
+	StateInKey                            // in an object key, like `{"a`
+	StateAfterKey                         // after an object key, expecting its value, like `{"a"` or `{"a":`
+	StateInStringValue                    // in a string value, like `{"a":"b`
+	StateInNumber                         // in a number, like `{"a":-1.5`
+	StateInLiteral                        // in a literal, like `{"a":tr`
+	StateInArray                          // in an array, expecting an element, like `[` or `[1,`
+	StateDone                             // the top-level value finished, like `{}`
+)
+
+var streamStateNames = [...]string{
+	StateBetweenValues: "BetweenValues",
+	StateInKey:         "InKey",
+	StateAfterKey:      "AfterKey",
+	StateInStringValue: "InStringValue",
+	StateInNumber:      "InNumber",
+	StateInLiteral:     "InLiteral",
+	StateInArray:       "InArray",
+	StateDone:          "Done",
+}
+
+func (state StreamState) String() string {
+	if state < 0 || int(state) >= len(streamStateNames) {
+		return "StreamState(" + strconv.Itoa(int(state)) + ")"
+	}
+	return streamStateNames[state]
+}
+
+// StreamContext describes where the JSON stream currently is
+type StreamContext struct {
+	// JSON Pointer (RFC 6901) of the value in lexing or expected next, like `/summary` for `{"summary":"abc`,
+	// the partial key is used in StateInKey, and it is the innermost open container in StateBetweenValues
+	Path  string
+	Depth int         // count of open containers
+	State StreamState // syntactic state
+}
+
+// escape reference token of JSON Pointer
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// get the current context of JSON stream, it is the context before the first grammar error in lenient mode.
+// the path is tracked since the first call: the first call attaches a path tracker and replays the JSON content
+// received so far into it, which costs O(content) once, then every following byte is tracked by it too, and the keys
+// are copied into it, until the lexer is dropped. Reset() keeps it. call it once at the start of the stream
+// to pay only the per-byte cost
+func (lexer *Lexer) Context() StreamContext {
+	g := &lexer.grammar
+	if lexer.paths == nil && lexer.grammarError == nil {
+		_ = lexer.attachPathTracker()
+	}
+	context := StreamContext{Depth: len(g.containers)}
+
+	// the path of innermost container, and the slot in it
+	inObject := g.topContainer() == TOKEN_LEFT_BRACE
+	slot := ""
+	slotKnown := lexer.paths != nil && len(lexer.paths.frames) > 0
+	var path strings.Builder
+	if lexer.paths != nil {
+		frames := lexer.paths.frames
+		for i := 0; i < len(frames)-1; i++ {
+			path.WriteByte('/')
+			path.WriteString(frames[i].slot())
+		}
+		if slotKnown {
+			slot = frames[len(frames)-1].slot()
+		}
+	}
+	containerPath := path.String()
+	slotPath := containerPath
+	if slotKnown {
+		slotPath = containerPath + "/" + slot
+	}
+
+	switch g.state {
+	case grammarStateDone:
+		context.State = StateDone
+		slotPath = ""
+	case grammarStateString, grammarStateStringEscape, grammarStateStringUnicode:
+		context.State = StateInStringValue
+		if g.inKey {
+			context.State = StateInKey
+		}
+	case grammarStateNumber:
+		context.State = StateInNumber
+	case grammarStateLiteral:
+		context.State = StateInLiteral
+	case grammarStateObjectColon:
+		context.State = StateAfterKey
+	case grammarStateValue:
+		switch {
+		case len(g.containers) == 0:
+			context.State = StateBetweenValues
+		case inObject:
+			context.State = StateAfterKey
+		default:
+			context.State = StateInArray
+			slotPath = containerPath + "/" + strconv.Itoa(lexer.nextArrayIndex())
+		}
+	case grammarStateArrayValueOrEnd:
+		context.State = StateInArray
+		slotPath = containerPath + "/" + strconv.Itoa(lexer.nextArrayIndex())
+	default:
+		// expecting a key, a comma or a closing token
+		context.State = StateBetweenValues
+		slotPath = containerPath
+	}
+	if lexer.paths != nil {
+		context.Path = slotPath
+	}
+	return context
+}
+
+// get index of the next element in the innermost array
+func (lexer *Lexer) nextArrayIndex() int {
+	if lexer.paths == nil || len(lexer.paths.frames) == 0 {
+		return 0
+	}
+	return lexer.paths.frames[len(lexer.paths.frames)-1].index + 1
+}
+
+// get reference token of the slot in lexing of the container
+func (frame *pathFrame) slot() string {
+	if frame.isObject {
+		return jsonPointerEscaper.Replace(string(frame.key))
+	}
+	return strconv.Itoa(frame.index)
+}
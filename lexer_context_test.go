@@ -0,0 +1,45 @@
+package streamingjsongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	streamingJSONCase := map[string]StreamContext{
+		``:                          {Path: ``, Depth: 0, State: StateBetweenValues},
+		`{`:                         {Path: ``, Depth: 1, State: StateBetweenValues},
+		`{"summ`:                    {Path: `/summ`, Depth: 1, State: StateInKey},
+		`{"summary"`:                {Path: `/summary`, Depth: 1, State: StateAfterKey},
+		`{"summary": `:              {Path: `/summary`, Depth: 1, State: StateAfterKey},
+		`{"summary": "ab`:           {Path: `/summary`, Depth: 1, State: StateInStringValue},
+		`{"summary": "ab\u00`:       {Path: `/summary`, Depth: 1, State: StateInStringValue},
+		`{"summary": "ab",`:         {Path: ``, Depth: 1, State: StateBetweenValues},
+		`{"a/b": {"c~d": [`:         {Path: `/a~1b/c~0d/0`, Depth: 3, State: StateInArray},
+		`{"a": [1, {"b": -1.`:       {Path: `/a/1/b`, Depth: 3, State: StateInNumber},
+		`{"a": [1, {"b": 2}`:        {Path: `/a`, Depth: 2, State: StateBetweenValues},
+		`{"a": [1, {"b": 2}, `:      {Path: `/a/2`, Depth: 2, State: StateInArray},
+		`{"a": [1, {"b": 2}, nu`:    {Path: `/a/2`, Depth: 2, State: StateInLiteral},
+		`{"a": [1, {"b": 2}, null]`: {Path: ``, Depth: 1, State: StateBetweenValues},
+		`[[], [], 1`:                {Path: `/2`, Depth: 1, State: StateInNumber},
+		`"str`:                      {Path: ``, Depth: 0, State: StateInStringValue},
+		`{"a": {}} `:                {Path: ``, Depth: 0, State: StateDone},
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer()
+		assert.Nil(t, lexer.AppendString(testCase))
+		assert.Equal(t, expect, lexer.Context(), "unexpected context in case: %s", testCase)
+	}
+}
+
+func TestContext_streaming(t *testing.T) {
+	// the path is tracked after the first call
+	lexer := NewLexer()
+	assert.Equal(t, StateBetweenValues, lexer.Context().State)
+	assert.Nil(t, lexer.AppendString(`{"items": [{"title": "a"}, {"ti`))
+	assert.Equal(t, StreamContext{Path: `/items/1/ti`, Depth: 3, State: StateInKey}, lexer.Context())
+	assert.Nil(t, lexer.AppendString(`tle": "b`))
+	assert.Equal(t, StreamContext{Path: `/items/1/title`, Depth: 3, State: StateInStringValue}, lexer.Context())
+	assert.Equal(t, "InStringValue", lexer.Context().State.String())
+}
@@ -0,0 +1,419 @@
+package streamingjsongo
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// state of a struct field reported by PartialFields
+type FieldState int
+
+const (
+	FieldMissing   FieldState = iota // the field has not arrived yet
+	FieldStreaming                   // the field is arriving, its value is partial
+	FieldComplete                    // the field is complete, its value will not change
+)
+
+// PartialFields reports states of struct fields decoded by DecodePartial(), keyed by JSON name of the field.
+// add it into a struct as a field, it is filled by DecodePartial(), like:
+//
+//	type ToolCall struct {
+//		Name      string                      `json:"name"`
+//		Arguments map[string]interface{}      `json:"arguments"`
+//		Fields    streamingjson.PartialFields `json:"-"`
+//	}
+type PartialFields map[string]FieldState
+
+// check if the field by given JSON name is complete
+func (fields PartialFields) Complete(name string) bool {
+	return fields[name] == FieldComplete
+}
+
+var (
+	partialFieldsType = reflect.TypeOf(PartialFields(nil))
+	numberType        = reflect.TypeOf(json.Number(""))
+)
+
+// field of struct decoded by JSON name
+type structField struct {
+	name  string // JSON name of field
+	index []int  // index sequence of field for reflect.Value.FieldByIndex()
+}
+
+// decodable fields of struct
+type structFields struct {
+	fields        []structField
+	partialFields []int // index sequence of PartialFields field, nil if the struct has no PartialFields field
+}
+
+// cached fields of struct types, map[reflect.Type]*structFields
+var structFieldsCache sync.Map
+
+// get decodable fields of struct type
+func cachedStructFields(t reflect.Type) *structFields {
+	if cached, ok := structFieldsCache.Load(t); ok {
+		return cached.(*structFields)
+	}
+	fields := &structFields{}
+	collectStructFields(t, nil, fields)
+	cached, _ := structFieldsCache.LoadOrStore(t, fields)
+	return cached.(*structFields)
+}
+
+// collect fields of struct type with given index prefix, the fields of embedded structs and struct pointers are promoted
+func collectStructFields(t reflect.Type, indexPrefix []int, fields *structFields) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		index := append(append([]int{}, indexPrefix...), i)
+		if field.Type == partialFieldsType {
+			if fields.partialFields == nil {
+				fields.partialFields = index
+			}
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := tag
+		if comma := strings.IndexByte(tag, ','); comma >= 0 {
+			name = tag[:comma]
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			collectStructFields(field.Type, index, fields)
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct {
+			// the pointer of unexported struct can not be allocated
+			if field.PkgPath == "" {
+				collectStructFields(field.Type.Elem(), index, fields)
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			// unexported field
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields.fields = append(fields.fields, structField{name: name, index: index})
+	}
+}
+
+// find field by JSON key, the exact match is preferred, or matched case-insensitively like encoding/json does
+func (fields *structFields) find(key string) *structField {
+	var folded *structField
+	for i := range fields.fields {
+		if fields.fields[i].name == key {
+			return &fields.fields[i]
+		}
+		if folded == nil && strings.EqualFold(fields.fields[i].name, key) {
+			folded = &fields.fields[i]
+		}
+	}
+	return folded
+}
+
+// partial decoder decodes the partial value into Go values
+type partialDecoder struct {
+	builder *valueBuilder
+	err     error // first error in decoding, the decoding keeps going like encoding/json does
+}
+
+// record the first type error
+func (decoder *partialDecoder) typeError(value string, t reflect.Type) {
+	if decoder.err == nil {
+		decoder.err = &json.UnmarshalTypeError{Value: value, Type: t}
+	}
+}
+
+// record the first error of a complete value, the partial value in lexing may be rejected until it completes
+func (decoder *partialDecoder) valueError(err error, open bool) {
+	if err != nil && !open && decoder.err == nil {
+		decoder.err = err
+	}
+}
+
+// get the JSON type name of the partial value for type errors
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case json.Number:
+		return "number"
+	case bool:
+		return "bool"
+	}
+	return "string"
+}
+
+// get the field of struct by index sequence, the nil embedded struct pointers on the way are allocated
+func fieldByIndex(target reflect.Value, index []int) reflect.Value {
+	for i, fieldIndex := range index {
+		if i > 0 && target.Kind() == reflect.Ptr {
+			if target.IsNil() {
+				target.Set(reflect.New(target.Type().Elem()))
+			}
+			target = target.Elem()
+		}
+		target = target.Field(fieldIndex)
+	}
+	return target
+}
+
+// decode value into target by its json.Unmarshaler, or encoding.TextUnmarshaler for strings,
+// returns false if target implements neither. the partial value in lexing is passed as it is completed now
+func (decoder *partialDecoder) decodeUnmarshaler(value interface{}, target reflect.Value, open bool) bool {
+	if target.Kind() == reflect.Ptr || !target.CanAddr() {
+		return false
+	}
+	switch unmarshaler := target.Addr().Interface().(type) {
+	case json.Unmarshaler:
+		raw, err := json.Marshal(value)
+		if err == nil {
+			err = unmarshaler.UnmarshalJSON(raw)
+		}
+		decoder.valueError(err, open)
+		return true
+	case encoding.TextUnmarshaler:
+		text, ok := value.(string)
+		if !ok {
+			decoder.typeError(jsonTypeName(value), target.Type())
+			return true
+		}
+		decoder.valueError(unmarshaler.UnmarshalText([]byte(text)), open)
+		return true
+	}
+	return false
+}
+
+// decode base64 string into byte slice like encoding/json does, only the complete quanta of the partial string are decoded
+func (decoder *partialDecoder) decodeBytes(text string, target reflect.Value, open bool) {
+	if open {
+		text = text[:len(text)-len(text)%4]
+	}
+	b, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		decoder.valueError(err, open)
+		return
+	}
+	target.SetBytes(b)
+}
+
+// check if the child of an open value at given depth is open too
+func (decoder *partialDecoder) openChild(depth int, open bool, key string, index int) bool {
+	if !open {
+		return false
+	}
+	openKey, openIndex, ok := decoder.builder.openSlot(depth)
+	return ok && openKey == key && openIndex == index
+}
+
+// decode value into target, the value at given depth is in lexing if open is true
+func (decoder *partialDecoder) decode(value interface{}, target reflect.Value, depth int, open bool) {
+	if value == nil {
+		switch target.Kind() {
+		case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+			target.Set(reflect.Zero(target.Type()))
+		}
+		return
+	}
+	if target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		decoder.decode(value, target.Elem(), depth, open)
+		return
+	}
+	if decoder.decodeUnmarshaler(value, target, open) {
+		return
+	}
+	if target.Kind() == reflect.Interface && target.NumMethod() == 0 {
+		target.Set(reflect.ValueOf(copyValue(value, true)))
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		switch target.Kind() {
+		case reflect.Struct:
+			decoder.decodeStruct(v, target, depth, open)
+		case reflect.Map:
+			decoder.decodeMap(v, target, depth, open)
+		default:
+			decoder.typeError("object", target.Type())
+		}
+	case []interface{}:
+		switch target.Kind() {
+		case reflect.Slice:
+			if target.IsNil() || target.Cap() < len(v) {
+				target.Set(reflect.MakeSlice(target.Type(), len(v), len(v)))
+			} else {
+				target.SetLen(len(v))
+			}
+			decoder.decodeArray(v, target, depth, open)
+		case reflect.Array:
+			decoder.decodeArray(v, target, depth, open)
+			for i := len(v); i < target.Len(); i++ {
+				target.Index(i).Set(reflect.Zero(target.Type().Elem()))
+			}
+		default:
+			decoder.typeError("array", target.Type())
+		}
+	case string:
+		if target.Kind() == reflect.Slice && target.Type().Elem().Kind() == reflect.Uint8 {
+			decoder.decodeBytes(v, target, open)
+			return
+		}
+		if target.Kind() != reflect.String {
+			decoder.typeError("string", target.Type())
+			return
+		}
+		target.SetString(v)
+	case json.Number:
+		decoder.decodeNumber(v, target, open)
+	case bool:
+		if target.Kind() != reflect.Bool {
+			decoder.typeError("bool", target.Type())
+			return
+		}
+		target.SetBool(v)
+	}
+}
+
+// decode object into struct, and fill the PartialFields field of struct
+func (decoder *partialDecoder) decodeStruct(object map[string]interface{}, target reflect.Value, depth int, open bool) {
+	fields := cachedStructFields(target.Type())
+	var partialFields PartialFields
+	if fields.partialFields != nil {
+		partialFields = make(PartialFields, len(object))
+		fieldByIndex(target, fields.partialFields).Set(reflect.ValueOf(partialFields))
+	}
+	for key, value := range object {
+		field := fields.find(key)
+		if field == nil {
+			continue
+		}
+		childOpen := decoder.openChild(depth, open, key, 0)
+		decoder.decode(value, fieldByIndex(target, field.index), depth+1, childOpen)
+		if partialFields != nil {
+			if childOpen {
+				partialFields[field.name] = FieldStreaming
+			} else {
+				partialFields[field.name] = FieldComplete
+			}
+		}
+	}
+}
+
+// decode object into map with string keys
+func (decoder *partialDecoder) decodeMap(object map[string]interface{}, target reflect.Value, depth int, open bool) {
+	mapType := target.Type()
+	if mapType.Key().Kind() != reflect.String {
+		decoder.typeError("object", mapType)
+		return
+	}
+	if target.IsNil() {
+		target.Set(reflect.MakeMapWithSize(mapType, len(object)))
+	}
+	for key, value := range object {
+		element := reflect.New(mapType.Elem()).Elem()
+		decoder.decode(value, element, depth+1, decoder.openChild(depth, open, key, 0))
+		target.SetMapIndex(reflect.ValueOf(key).Convert(mapType.Key()), element)
+	}
+}
+
+// decode array into slice or array, the target length is already set for slices
+func (decoder *partialDecoder) decodeArray(array []interface{}, target reflect.Value, depth int, open bool) {
+	for i, value := range array {
+		if i >= target.Len() {
+			break
+		}
+		decoder.decode(value, target.Index(i), depth+1, decoder.openChild(depth, open, "", i))
+	}
+}
+
+// decode number, a partial number like `12.0` completed from `12.` is accepted by integer targets
+func (decoder *partialDecoder) decodeNumber(number json.Number, target reflect.Value, open bool) {
+	if target.Type() == numberType {
+		target.SetString(string(number))
+		return
+	}
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(string(number), 10, 64)
+		if err != nil && open {
+			n, err = parseIntegralFloat(string(number))
+		}
+		if err != nil || target.OverflowInt(n) {
+			decoder.typeError("number "+string(number), target.Type())
+			return
+		}
+		target.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(string(number), 10, 64)
+		if err != nil && open {
+			var signed int64
+			signed, err = parseIntegralFloat(string(number))
+			if signed < 0 {
+				err = strconv.ErrRange
+			}
+			n = uint64(signed)
+		}
+		if err != nil || target.OverflowUint(n) {
+			decoder.typeError("number "+string(number), target.Type())
+			return
+		}
+		target.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(string(number), target.Type().Bits())
+		if err != nil || target.OverflowFloat(n) {
+			decoder.typeError("number "+string(number), target.Type())
+			return
+		}
+		target.SetFloat(n)
+	default:
+		decoder.typeError("number", target.Type())
+	}
+}
+
+// parse number with integral value in float form, like `12.0` or `1e2`
+func parseIntegralFloat(number string) (int64, error) {
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, err
+	}
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, strconv.ErrRange
+	}
+	return int64(f), nil
+}
+
+// decode the partial value of JSON stream into v like json.Unmarshal() does, without re-parsing the completed JSON.
+// the PartialFields fields of structs are filled with the states of struct fields, so it tells which fields are
+// complete and which are still streaming. like encoding/json, json.Unmarshaler and encoding.TextUnmarshaler implementations
+// are called, []byte is decoded from base64 strings, and the fields of embedded structs and struct pointers are promoted.
+// the unmarshalers are called with the partial value completed as CompleteJSON() does on every call,
+// their errors are ignored until the value completes, so are the errors of partial base64 strings.
+func (lexer *Lexer) DecodePartial(v interface{}) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+	value, err := lexer.Value()
+	if err != nil {
+		return err
+	}
+	decoder := partialDecoder{builder: lexer.value}
+	decoder.decode(value, target.Elem(), 0, lexer.grammar.state != grammarStateDone)
+	return decoder.err
+}
@@ -0,0 +1,184 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testLocation struct {
+	City string  `json:"city"`
+	Days int     `json:"days"`
+	Temp float64 `json:"temp"`
+}
+
+type testToolCall struct {
+	Name      string                 `json:"name"`
+	Locations []testLocation         `json:"locations"`
+	Options   map[string]interface{} `json:"options"`
+	Verbose   *bool                  `json:"verbose,omitempty"`
+	Ignored   string                 `json:"-"`
+	Fields    PartialFields          `json:"-"`
+}
+
+func TestDecodePartial(t *testing.T) {
+	streamingJSONCase := map[string]testToolCall{
+		`{"na`: {
+			Fields: PartialFields{},
+		},
+		`{"name":"get_wea`: {
+			Name:   "get_wea",
+			Fields: PartialFields{"name": FieldStreaming},
+		},
+		`{"name":"get_weather", "locations":[{"city":"Paris","days":3`: {
+			Name:      "get_weather",
+			Locations: []testLocation{{City: "Paris", Days: 3}},
+			Fields:    PartialFields{"name": FieldComplete, "locations": FieldStreaming},
+		},
+		`{"name":"get_weather", "locations":[{"city":"Paris","days":3.`: {
+			Name:      "get_weather",
+			Locations: []testLocation{{City: "Paris", Days: 3}},
+			Fields:    PartialFields{"name": FieldComplete, "locations": FieldStreaming},
+		},
+		`{"name":"get_weather", "locations":[], "options":{"unit":"c", "n":2}, "Ignored":"x", "VERBOSE":tr`: {
+			Name:      "get_weather",
+			Locations: []testLocation{},
+			Options:   map[string]interface{}{"unit": "c", "n": float64(2)},
+			Verbose:   func() *bool { b := true; return &b }(),
+			Fields:    PartialFields{"name": FieldComplete, "locations": FieldComplete, "options": FieldComplete, "verbose": FieldStreaming},
+		},
+		`{"name":"get_weather", "locations":null}`: {
+			Name:   "get_weather",
+			Fields: PartialFields{"name": FieldComplete, "locations": FieldComplete},
+		},
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer()
+		assert.Nil(t, lexer.AppendString(testCase))
+		var toolCall testToolCall
+		assert.Nil(t, lexer.DecodePartial(&toolCall))
+		assert.Equal(t, expect, toolCall, "unexpected struct in case: %s", testCase)
+	}
+}
+
+func TestDecodePartial_nestedFields(t *testing.T) {
+	type location struct {
+		City   string        `json:"city"`
+		Fields PartialFields `json:"-"`
+	}
+	type toolCall struct {
+		Locations []location `json:"locations"`
+	}
+	lexer := NewLexer()
+	assert.Nil(t, lexer.AppendString(`{"locations":[{"city":"Paris"},{"city":"Lon`))
+	var call toolCall
+	assert.Nil(t, lexer.DecodePartial(&call))
+	assert.Equal(t, 2, len(call.Locations))
+	assert.True(t, call.Locations[0].Fields.Complete("city"))
+	assert.Equal(t, FieldStreaming, call.Locations[1].Fields["city"])
+	assert.Equal(t, "Lon", call.Locations[1].City)
+
+	assert.Nil(t, lexer.AppendString(`don"}]}`))
+	assert.Nil(t, lexer.DecodePartial(&call))
+	assert.True(t, call.Locations[1].Fields.Complete("city"))
+	assert.Equal(t, "London", call.Locations[1].City)
+}
+
+func TestDecodePartial_error(t *testing.T) {
+	lexer := NewLexer()
+	assert.Nil(t, lexer.AppendString(`{"name":1, "locations":[{"city":"Paris"}]`))
+
+	var toolCall testToolCall
+	assert.IsType(t, &json.InvalidUnmarshalError{}, lexer.DecodePartial(toolCall))
+
+	// the decoding keeps going after a type error like encoding/json does
+	err := lexer.DecodePartial(&toolCall)
+	assert.IsType(t, &json.UnmarshalTypeError{}, err)
+	assert.Equal(t, []testLocation{{City: "Paris"}}, toolCall.Locations)
+
+	lexer.Reset()
+	assert.Nil(t, lexer.AppendString(`[1 2`))
+	var numbers []int
+	assert.IsType(t, &SyntaxError{}, lexer.DecodePartial(&numbers))
+}
+
+// level implements encoding.TextUnmarshaler
+type testLevel int
+
+func (level *testLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*level = 1
+	case "high":
+		*level = 2
+	default:
+		return fmt.Errorf("unknown level %q", text)
+	}
+	return nil
+}
+
+// upper implements json.Unmarshaler
+type testUpper string
+
+func (upper *testUpper) UnmarshalJSON(raw []byte) error {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return err
+	}
+	*upper = testUpper(strings.ToUpper(s))
+	return nil
+}
+
+// exported, the pointer of unexported embedded struct can not be allocated
+type TestReportMeta struct {
+	Source string `json:"source"`
+}
+
+type testReport struct {
+	*TestReportMeta
+	Title   testUpper       `json:"title"`
+	Level   testLevel       `json:"level"`
+	Data    []byte          `json:"data"`
+	Raw     json.RawMessage `json:"raw"`
+	Created time.Time       `json:"created"`
+}
+
+func TestDecodePartial_unmarshalers(t *testing.T) {
+	stream := `{"source": "api", "title": "weather", "level": "high", "data": "aGVsbG8gd29ybGQ=", "raw": {"a":[1,2]}, "created": "2024-05-01T10:00:00Z"}`
+	lexer := NewLexer()
+	for i := 0; i < len(stream); i++ {
+		assert.Nil(t, lexer.AppendString(stream[i:i+1]))
+		var report testReport
+		// the errors of partial values are ignored until they complete
+		assert.Nil(t, lexer.DecodePartial(&report), "unexpected error at: %s", stream[:i+1])
+	}
+	var report testReport
+	assert.Nil(t, lexer.DecodePartial(&report))
+	var expect testReport
+	assert.Nil(t, json.Unmarshal([]byte(stream), &expect))
+	assert.Equal(t, expect, report)
+	assert.Equal(t, "api", report.Source)
+	assert.Equal(t, testUpper("WEATHER"), report.Title)
+	assert.Equal(t, testLevel(2), report.Level)
+	assert.Equal(t, []byte("hello world"), report.Data)
+
+	// only the complete quanta of a partial base64 string are decoded
+	lexer.Reset()
+	assert.Nil(t, lexer.AppendString(`{"data": "aGVsbG8gd2`))
+	report = testReport{}
+	assert.Nil(t, lexer.DecodePartial(&report))
+	assert.Equal(t, []byte("hello "), report.Data)
+	assert.Nil(t, report.TestReportMeta)
+
+	// the errors of complete values are returned
+	for _, testCase := range []string{`{"level": "mid"}`, `{"data": "!!"}`, `{"level": 1}`} {
+		lexer.Reset()
+		assert.Nil(t, lexer.AppendString(testCase))
+		report = testReport{}
+		assert.NotNil(t, lexer.DecodePartial(&report), "expected error in case: %s", testCase)
+	}
+}
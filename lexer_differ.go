@@ -0,0 +1,251 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// operation of JSON Patch (RFC 6902)
+type PatchOperation struct {
+	Op    string          `json:"op"`              // `add`, `remove`, `replace`, or `append` of strings
+	Path  string          `json:"path"`            // JSON Pointer (RFC 6901) of the target
+	Value json.RawMessage `json:"value,omitempty"` // JSON of the value, empty for `remove`
+}
+
+// Differ emits JSON Patch (RFC 6902) operations between successive partial values of a JSON stream,
+// so the receiver applying them keeps the same value as CompleteJSON() without receiving the whole JSON again.
+// the slots changed are tracked by grammar events, so a Diff() call walks only the values changed since the last one
+type Differ struct {
+	previous    interface{} // snapshot of the partial value at the last Diff(), updated slot by slot
+	hasPrevious bool        // if the snapshot is taken
+}
+
+// new differ for a JSON stream
+func NewDiffer() *Differ {
+	return &Differ{}
+}
+
+// reset the differ for a new JSON stream
+func (differ *Differ) Reset() {
+	differ.previous = nil
+	differ.hasPrevious = false
+}
+
+// diff the partial value of lexer with the one at the last call, returns the operations from the last one to current one.
+// the first call returns an `add` operation of the whole document. a string grew is sent by an `append` operation
+// with the bytes appended only, it is not in RFC 6902, the receiver appends the value to the string at the path
+func (differ *Differ) Diff(lexer *Lexer) ([]PatchOperation, error) {
+	value, err := lexer.Value()
+	if err != nil {
+		return nil, err
+	}
+	if !differ.hasPrevious && lexer.grammar.state == grammarStateValue && len(lexer.grammar.containers) == 0 {
+		// the top-level value is not started yet
+		return nil, nil
+	}
+	full, err := differ.track(lexer)
+	if err != nil {
+		return nil, err
+	}
+	_, held := lexer.heldValue()
+	lexer.paths.markOpenSlot(held)
+	var operations []PatchOperation
+	switch {
+	case !differ.hasPrevious:
+		operations, err = appendPatchOperation(operations, "add", "", value)
+		differ.previous = copyValue(value, false)
+	case full:
+		operations, err = differ.diffAll(operations, value)
+	default:
+		operations, err = differ.diffSlots(operations, lexer.paths.dirtySlots, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	differ.hasPrevious = true
+	lexer.paths.startDiff(held)
+	return operations, nil
+}
+
+// track the slots changed by the path tracker of lexer, returns true if the whole value must be diffed
+func (differ *Differ) track(lexer *Lexer) (bool, error) {
+	if lexer.paths == nil {
+		if err := lexer.attachPathTracker(); err != nil {
+			return false, err
+		}
+	}
+	tracker := lexer.paths
+	full := tracker.differ != differ || tracker.dirtyAll
+	tracker.differ = differ
+	return full, nil
+}
+
+// diff the whole value with the snapshot
+func (differ *Differ) diffAll(operations []PatchOperation, value interface{}) ([]PatchOperation, error) {
+	operations, err := diffValue(operations, "", differ.previous, value)
+	differ.previous = copyValue(value, false)
+	return operations, err
+}
+
+// diff the slots changed since the last call, the slots in the slot diffed already are skipped
+func (differ *Differ) diffSlots(operations []PatchOperation, slots [][]string, value interface{}) ([]PatchOperation, error) {
+	diffed := make(map[string]bool, len(slots))
+	for _, tokens := range slots {
+		pointer := ""
+		covered := false
+		for _, token := range tokens {
+			if diffed[pointer] {
+				covered = true
+				break
+			}
+			pointer += "/" + jsonPointerEscaper.Replace(token)
+		}
+		if covered || diffed[pointer] {
+			continue
+		}
+		diffed[pointer] = true
+		previous, hadPrevious := lookupJSONPointer(differ.previous, tokens)
+		current, hasCurrent := lookupJSONPointer(value, tokens)
+		if !hadPrevious && !hasCurrent {
+			continue
+		}
+		if !storeJSONPointer(&differ.previous, tokens, copyValue(current, false), hasCurrent) {
+			// the container of the slot is not in the snapshot
+			return differ.diffAll(operations, value)
+		}
+		var err error
+		switch {
+		case !hasCurrent:
+			operations = append(operations, PatchOperation{Op: "remove", Path: pointer})
+		case !hadPrevious:
+			operations, err = appendPatchOperation(operations, "add", pointer, current)
+		default:
+			operations, err = diffValue(operations, pointer, previous, current)
+		}
+		if err != nil {
+			return operations, err
+		}
+	}
+	return operations, nil
+}
+
+// store value into the snapshot at JSON Pointer reference tokens, or remove the slot if the value is not present.
+// returns false without changing the snapshot if the container of the slot is not in it
+func storeJSONPointer(slot *interface{}, tokens []string, value interface{}, present bool) bool {
+	if len(tokens) == 0 {
+		*slot = value
+		return true
+	}
+	switch container := (*slot).(type) {
+	case map[string]interface{}:
+		if len(tokens) > 1 {
+			member, ok := container[tokens[0]]
+			if !ok || !storeJSONPointer(&member, tokens[1:], value, present) {
+				return false
+			}
+			container[tokens[0]] = member
+		} else if present {
+			container[tokens[0]] = value
+		} else {
+			delete(container, tokens[0])
+		}
+		return true
+	case []interface{}:
+		index := jsonPointerIndex(tokens[0])
+		switch {
+		case index < 0 || index > len(container):
+			return false
+		case len(tokens) > 1:
+			return index < len(container) && storeJSONPointer(&container[index], tokens[1:], value, present)
+		case present && index == len(container):
+			*slot = append(container, value)
+		case present:
+			container[index] = value
+		case index == len(container)-1:
+			// only the last element can be omitted
+			*slot = container[:index]
+		default:
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// append an operation with the JSON of value
+func appendPatchOperation(operations []PatchOperation, op string, path string, value interface{}) ([]PatchOperation, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return operations, err
+	}
+	return append(operations, PatchOperation{Op: op, Path: path, Value: raw}), nil
+}
+
+// diff the previous value and current value at path
+func diffValue(operations []PatchOperation, path string, previous interface{}, current interface{}) ([]PatchOperation, error) {
+	var err error
+	switch currentValue := current.(type) {
+	case map[string]interface{}:
+		previousValue, ok := previous.(map[string]interface{})
+		if !ok {
+			return appendPatchOperation(operations, "replace", path, current)
+		}
+		// removed keys are partial keys grew, like `{"a` to `{"ab`
+		for _, key := range sortedKeys(previousValue) {
+			if _, ok := currentValue[key]; !ok {
+				operations = append(operations, PatchOperation{Op: "remove", Path: path + "/" + jsonPointerEscaper.Replace(key)})
+			}
+		}
+		for _, key := range sortedKeys(currentValue) {
+			memberPath := path + "/" + jsonPointerEscaper.Replace(key)
+			previousMember, ok := previousValue[key]
+			if !ok {
+				operations, err = appendPatchOperation(operations, "add", memberPath, currentValue[key])
+			} else {
+				operations, err = diffValue(operations, memberPath, previousMember, currentValue[key])
+			}
+			if err != nil {
+				return operations, err
+			}
+		}
+		return operations, nil
+	case []interface{}:
+		previousValue, ok := previous.([]interface{})
+		if !ok || len(previousValue) > len(currentValue) {
+			return appendPatchOperation(operations, "replace", path, current)
+		}
+		for i, element := range currentValue {
+			elementPath := path + "/" + strconv.Itoa(i)
+			if i < len(previousValue) {
+				operations, err = diffValue(operations, elementPath, previousValue[i], element)
+			} else {
+				operations, err = appendPatchOperation(operations, "add", elementPath, element)
+			}
+			if err != nil {
+				return operations, err
+			}
+		}
+		return operations, nil
+	case string:
+		// a string grew, like `{"a":"x` to `{"a":"xyz`
+		if previousValue, ok := previous.(string); ok && len(previousValue) < len(currentValue) && strings.HasPrefix(currentValue, previousValue) {
+			return appendPatchOperation(operations, "append", path, currentValue[len(previousValue):])
+		}
+	}
+	if previous == current {
+		return operations, nil
+	}
+	return appendPatchOperation(operations, "replace", path, current)
+}
+
+// get sorted keys of object, so the operations are in stable order
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
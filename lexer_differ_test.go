@@ -0,0 +1,164 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// apply JSON Patch operations of Differ to the document
+func applyTestPatch(t *testing.T, document interface{}, operations []PatchOperation) interface{} {
+	for _, operation := range operations {
+		var value interface{}
+		if operation.Op != "remove" {
+			decoder := json.NewDecoder(strings.NewReader(string(operation.Value)))
+			decoder.UseNumber()
+			assert.Nil(t, decoder.Decode(&value))
+		}
+		tokens, err := parseJSONPointer(operation.Path)
+		assert.Nil(t, err)
+		document = applyTestOperation(document, tokens, operation.Op, value)
+	}
+	return document
+}
+
+func applyTestOperation(document interface{}, tokens []string, op string, value interface{}) interface{} {
+	if len(tokens) == 0 {
+		if op == "append" {
+			return document.(string) + value.(string)
+		}
+		return value
+	}
+	switch container := document.(type) {
+	case map[string]interface{}:
+		if len(tokens) > 1 {
+			container[tokens[0]] = applyTestOperation(container[tokens[0]], tokens[1:], op, value)
+		} else if op == "remove" {
+			delete(container, tokens[0])
+		} else if op == "append" {
+			container[tokens[0]] = container[tokens[0]].(string) + value.(string)
+		} else {
+			container[tokens[0]] = value
+		}
+		return container
+	case []interface{}:
+		index, _ := strconv.Atoi(tokens[0])
+		if len(tokens) > 1 {
+			container[index] = applyTestOperation(container[index], tokens[1:], op, value)
+		} else if op == "add" {
+			container = append(container[:index], append([]interface{}{value}, container[index:]...)...)
+		} else if op == "remove" {
+			container = append(container[:index], container[index+1:]...)
+		} else if op == "append" {
+			container[index] = container[index].(string) + value.(string)
+		} else {
+			container[index] = value
+		}
+		return container
+	}
+	return document
+}
+
+func TestDiffer(t *testing.T) {
+	streamingJSONContent := `{"title": "weather", "items": [{"city": "Paris", "temp": -1.5e1}, {"city": "Lon", "tags": ["a", "b"]}], "ok": true, "a/b": null}`
+	lexer := NewLexer()
+	differ := NewDiffer()
+	var document interface{}
+	for i := 0; i < len(streamingJSONContent); i++ {
+		assert.Nil(t, lexer.AppendString(streamingJSONContent[i:i+1]))
+		operations, err := differ.Diff(lexer)
+		assert.Nil(t, err)
+		document = applyTestPatch(t, document, operations)
+		if !assert.Equal(t, decodeCompletedJSON(t, lexer.CompleteJSON()), document, "unexpected document at: %s", streamingJSONContent[:i+1]) {
+			break
+		}
+	}
+}
+
+func TestDiffer_options(t *testing.T) {
+	// values hidden, omitted or held before lexing are diffed as CompleteJSON() completes them
+	streamingJSONCase := map[string][]Option{
+		`{"a": [1, 22, "x\u00e9y"], "b": {"c": "long text", "d": 3}, "e": [[], {}]}`: {WithAtomicValues(CompleteOmit)},
+		`{"a": "x", "b": [1, 2], "c": {"d": "e"}}`:                                   {WithOmitDanglingKeys()},
+		`{a: [0x1F, -Infinity, +.5], b: 'it\'s', c: NaN}`:                            {WithRelaxed()},
+		`{"a": [True, None, -1], "b": False}`:                                        {WithPythonLiterals()},
+		"{\"a\": 1}\n[\"b\", 2]\n\"c\"\n":                                            {WithMultipleDocuments()},
+	}
+	for streamingJSONContent, options := range streamingJSONCase {
+		lexer := NewLexer(options...)
+		differ := NewDiffer()
+		var document interface{}
+		for i := 0; i < len(streamingJSONContent); i++ {
+			assert.Nil(t, lexer.AppendString(streamingJSONContent[i:i+1]))
+			operations, err := differ.Diff(lexer)
+			assert.Nil(t, err)
+			document = applyTestPatch(t, document, operations)
+			value, err := lexer.Value()
+			assert.Nil(t, err)
+			if !assert.Equal(t, value, document, "unexpected document at: %s", streamingJSONContent[:i+1]) {
+				break
+			}
+		}
+	}
+}
+
+func TestDiffer_work(t *testing.T) {
+	// the work of a call does not depend on the length of the document
+	allocs := map[int]float64{}
+	for _, elements := range []int{100, 10000} {
+		lexer := NewLexer()
+		differ := NewDiffer()
+		assert.Nil(t, lexer.AppendString(`{"items": [`+strings.Repeat(`{"a": [1, "x"]}, `, elements)+`{"text": "`))
+		_, err := differ.Diff(lexer)
+		assert.Nil(t, err)
+		var operations []PatchOperation
+		allocs[elements] = testing.AllocsPerRun(100, func() {
+			_ = lexer.AppendString("abc")
+			operations, _ = differ.Diff(lexer)
+		})
+		assert.Equal(t, []PatchOperation{{Op: "append", Path: fmt.Sprintf("/items/%d/text", elements), Value: json.RawMessage(`"abc"`)}}, operations)
+	}
+	assert.Equal(t, allocs[100], allocs[10000])
+}
+
+func TestDiffer_operations(t *testing.T) {
+	lexer := NewLexer()
+	differ := NewDiffer()
+	operations, err := differ.Diff(lexer)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(operations))
+
+	assert.Nil(t, lexer.AppendString(`{"a": "x`))
+	operations, err = differ.Diff(lexer)
+	assert.Nil(t, err)
+	assert.Equal(t, []PatchOperation{{Op: "add", Path: "", Value: json.RawMessage(`{"a":"x"}`)}}, operations)
+
+	assert.Nil(t, lexer.AppendString(`yz", "b": [1`))
+	operations, err = differ.Diff(lexer)
+	assert.Nil(t, err)
+	assert.Equal(t, []PatchOperation{
+		{Op: "append", Path: "/a", Value: json.RawMessage(`"yz"`)},
+		{Op: "add", Path: "/b", Value: json.RawMessage(`[1]`)},
+	}, operations)
+
+	assert.Nil(t, lexer.AppendString(`, 2], "c`))
+	operations, err = differ.Diff(lexer)
+	assert.Nil(t, err)
+	assert.Equal(t, []PatchOperation{
+		{Op: "add", Path: "/b/1", Value: json.RawMessage(`2`)},
+		{Op: "add", Path: "/c", Value: json.RawMessage(`null`)},
+	}, operations)
+
+	// nothing changed
+	operations, err = differ.Diff(lexer)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(operations))
+
+	raw, err := json.Marshal(PatchOperation{Op: "remove", Path: "/c"})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"op":"remove","path":"/c"}`, string(raw))
+}
@@ -0,0 +1,61 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+)
+
+// keep the top-level value completed in multiple documents mode as a document, then reset the lexer for the next document
+func (lexer *Lexer) finishDocument() {
+	// the updates of the document must be notified before the value of it is dropped
+	if lexer.paths != nil {
+		lexer.dispatchUpdates()
+	}
+	lexer.continuedLine = false
+	document := make(json.RawMessage, lexer.JSONContent.Len())
+	copy(document, lexer.JSONContent.Bytes())
+	lexer.documents = append(lexer.documents, document)
+	lexer.resetDocument()
+	if lexer.onDocument != nil {
+		lexer.onDocument(document)
+	}
+}
+
+// drop the broken document in multiple documents mode, the rest of its line is skipped,
+// so a bad line of NDJSON does not break the following lines. a raw newline in a string breaks the document
+// before its line ends, the rest of it on the next line is dropped without another error
+func (lexer *Lexer) dropDocument(syntaxError *SyntaxError, tokenSymbol byte) {
+	reported := !lexer.continuedLine
+	lexer.continuedLine = tokenSymbol == '\n'
+	if reported {
+		lexer.notifyError(syntaxError)
+	}
+	lexer.resetDocument()
+	lexer.resetStages()
+	if lexer.options.extractEmbedded && !lexer.extractor.fenced {
+		// the text after the broken body is searched for the next body
+		lexer.extractor.reset()
+	} else {
+		lexer.skipLine = tokenSymbol != '\n'
+	}
+	if reported && lexer.onDocumentError != nil {
+		lexer.onDocumentError(syntaxError)
+	}
+}
+
+// get the documents completed in multiple documents mode, in order of the JSON stream.
+// the documents are kept until Reset(), the trailing partial document is completed by CompleteJSON()
+func (lexer *Lexer) Documents() []json.RawMessage {
+	return lexer.documents
+}
+
+// set the callback of completed documents in multiple documents mode, callback is called with the raw JSON of each
+// top-level value as soon as it completed. a top-level number is not complete until a byte after it arrived
+func (lexer *Lexer) OnDocument(callback func(raw json.RawMessage)) {
+	lexer.onDocument = callback
+}
+
+// set the callback of dropped documents in multiple documents mode, callback is called with the grammar error
+// breaking the document, the document is not kept and the lexer continues after the next newline
+func (lexer *Lexer) OnDocumentError(callback func(err *SyntaxError)) {
+	lexer.onDocumentError = callback
+}
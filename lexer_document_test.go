@@ -0,0 +1,138 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMultipleDocuments(t *testing.T) {
+	lexer := NewLexer(WithMultipleDocuments())
+	assert.Nil(t, lexer.AppendString(`{"a":1}{"b":[2]}{"c":`))
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{"a":1}`), json.RawMessage(`{"b":[2]}`)}, lexer.Documents())
+	assert.Equal(t, `{"c":null}`, lexer.CompleteJSON())
+	value, err := lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"c": nil}, value)
+
+	lexer.Reset()
+	assert.Nil(t, lexer.Documents())
+	assert.Equal(t, ``, lexer.CompleteJSON())
+}
+
+func TestWithMultipleDocuments_ndjson(t *testing.T) {
+	stream := "{\"a\":1}\n[1, \"x\"]\r\n  \"s\"\n12\n-0.5 true\n{\"b\":\"hel"
+	expect := []json.RawMessage{
+		json.RawMessage(`{"a":1}`),
+		json.RawMessage(`[1, "x"]`),
+		json.RawMessage(`"s"`),
+		json.RawMessage(`12`),
+		json.RawMessage(`-0.5`),
+		json.RawMessage(`true`),
+	}
+	lexer := NewLexer(WithMultipleDocuments())
+	var documents []json.RawMessage
+	lexer.OnDocument(func(raw json.RawMessage) {
+		documents = append(documents, raw)
+	})
+	for i := 0; i < len(stream); i++ {
+		assert.Nil(t, lexer.AppendString(stream[i:i+1]))
+		completedJSON := lexer.CompleteJSON()
+		if completedJSON != `` {
+			assert.True(t, json.Valid([]byte(completedJSON)), "invalid completion %s of: %q", completedJSON, stream[:i+1])
+		}
+	}
+	assert.Equal(t, expect, documents)
+	assert.Equal(t, expect, lexer.Documents())
+	assert.Equal(t, `{"b":"hel"}`, lexer.CompleteJSON())
+}
+
+func TestWithMultipleDocuments_subscriptions(t *testing.T) {
+	lexer := NewLexer(WithMultipleDocuments())
+	var completed []string
+	assert.Nil(t, lexer.OnComplete("/a", func(raw json.RawMessage) {
+		completed = append(completed, string(raw))
+	}))
+	assert.Nil(t, lexer.AppendString("{\"a\":1}\n{\"a\":\"x\"}\n{\"a\":[t"))
+	assert.Equal(t, []string{`1`, `"x"`}, completed)
+	context := lexer.Context()
+	assert.Equal(t, "/a/0", context.Path)
+}
+
+func TestWithMultipleDocuments_strict(t *testing.T) {
+	lexer := NewLexer(WithMultipleDocuments(), WithStrict())
+	var dropped []*SyntaxError
+	lexer.OnDocumentError(func(err *SyntaxError) {
+		dropped = append(dropped, err)
+	})
+	assert.Nil(t, lexer.AppendString("{}\n[]\n"))
+	// the documents after the broken one in the same segment are still lexed
+	assert.Nil(t, lexer.AppendString("{]\n[1]\n"))
+	if assert.Len(t, dropped, 1) {
+		assert.Equal(t, int64(7), dropped[0].Offset)
+		assert.Equal(t, 3, dropped[0].Line)
+	}
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{}`), json.RawMessage(`[]`), json.RawMessage(`[1]`)}, lexer.Documents())
+}
+
+func TestWithMultipleDocuments_newlineInString(t *testing.T) {
+	// a raw newline breaks the string, the rest of the document on the next line is not reported again
+	stream := "{\"a\":\"x\ny\",\"b\":1}\n{\"c\":2}\n{\"d\" 3}\n"
+	for _, options := range [][]Option{{WithMultipleDocuments()}, {WithMultipleDocuments(), WithStrict()}} {
+		lexer := NewLexer(options...)
+		var dropped []*SyntaxError
+		lexer.OnDocumentError(func(err *SyntaxError) {
+			dropped = append(dropped, err)
+		})
+		assert.Nil(t, lexer.AppendString(stream))
+		assert.Equal(t, []json.RawMessage{json.RawMessage(`{"c":2}`)}, lexer.Documents())
+		if assert.Len(t, dropped, 2) {
+			assert.Equal(t, 1, dropped[0].Line)
+			assert.Equal(t, 4, dropped[1].Line)
+		}
+	}
+}
+
+func TestWithMultipleDocuments_brokenLine(t *testing.T) {
+	stream := "{\"a\":1}\n{\"b\" 2}\n{\"c\":3}\n]\n{\"d\":4}\n{\"e\":"
+	expect := []json.RawMessage{json.RawMessage(`{"a":1}`), json.RawMessage(`{"c":3}`), json.RawMessage(`{"d":4}`)}
+	// lenient mode
+	lexer := NewLexer(WithMultipleDocuments())
+	var dropped []*SyntaxError
+	lexer.OnDocumentError(func(err *SyntaxError) {
+		dropped = append(dropped, err)
+	})
+	assert.Nil(t, lexer.AppendString(stream))
+	assert.Equal(t, expect, lexer.Documents())
+	assert.Equal(t, `{"e":null}`, lexer.CompleteJSON())
+	value, err := lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"e": nil}, value)
+	if assert.Len(t, dropped, 2) {
+		assert.Equal(t, 2, dropped[0].Line)
+		assert.Equal(t, 4, dropped[1].Line)
+	}
+
+	// lenient mode byte by byte
+	lexer = NewLexer(WithMultipleDocuments())
+	for i := 0; i < len(stream); i++ {
+		assert.Nil(t, lexer.AppendString(stream[i:i+1]))
+		completedJSON := lexer.CompleteJSON()
+		if completedJSON != `` {
+			assert.True(t, json.Valid([]byte(completedJSON)), "invalid completion %s of: %q", completedJSON, stream[:i+1])
+		}
+	}
+	assert.Equal(t, expect, lexer.Documents())
+
+	// strict mode drops the broken documents too
+	lexer = NewLexer(WithMultipleDocuments(), WithStrict())
+	dropped = nil
+	lexer.OnDocumentError(func(err *SyntaxError) {
+		dropped = append(dropped, err)
+	})
+	assert.Nil(t, lexer.AppendString(stream))
+	assert.Len(t, dropped, 2)
+	assert.Equal(t, expect, lexer.Documents())
+	assert.Equal(t, `{"e":null}`, lexer.CompleteJSON())
+}
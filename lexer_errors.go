@@ -0,0 +1,54 @@
+package streamingjsongo
+
+import (
+	"fmt"
+)
+
+// bytes around the offending byte kept in the excerpt of a syntax error
+const syntaxErrorExcerptRadius = 16
+
+// position of a byte in JSON stream
+type streamPosition struct {
+	offset int64 // absolute byte offset across all appended segments
+	line   int   // newlines before the byte
+	column int   // bytes between the last newline and the byte
+}
+
+// advance position by given byte
+func (position *streamPosition) advance(c byte) {
+	position.offset++
+	if c == '\n' {
+		position.line++
+		position.column = 0
+		return
+	}
+	position.column++
+}
+
+// SyntaxError describes where and why the JSON stream broke
+type SyntaxError struct {
+	Msg     string // description of the error
+	Offset  int64  // absolute byte offset of the offending byte across all appended segments, starts from 0
+	Line    int    // line of the offending byte, starts from 1
+	Column  int    // column of the offending byte in bytes, starts from 1
+	Byte    byte   // the offending byte
+	Segment int    // index of the appended segment containing the offending byte, starts from 0
+	Excerpt string // short excerpt of the segment around the offending byte
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at line %d, column %d (offset %d, segment %d), near `%s`", e.Msg, e.Line, e.Column, e.Offset, e.Segment, e.Excerpt)
+}
+
+// range of excerpt around the byte at given index of segment
+func excerptRange(segmentLen int, index int) (int, int) {
+	excerptStart := index - syntaxErrorExcerptRadius
+	if excerptStart < 0 {
+		excerptStart = 0
+	}
+	excerptEnd := index + syntaxErrorExcerptRadius + 1
+	if excerptEnd > segmentLen {
+		excerptEnd = segmentLen
+	}
+	return excerptStart, excerptEnd
+}
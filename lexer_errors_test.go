@@ -0,0 +1,58 @@
+package streamingjsongo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyntaxError_position(t *testing.T) {
+	lexer := NewLexer(WithStrict())
+	segments := []string{"{\n  \"a\": [1,", "\n    2", " 3]}"}
+	var errInAppendString error
+	for _, segment := range segments {
+		if errInAppendString = lexer.AppendString(segment); errInAppendString != nil {
+			break
+		}
+	}
+
+	var syntaxError *SyntaxError
+	if !assert.True(t, errors.As(errInAppendString, &syntaxError)) {
+		return
+	}
+	assert.Equal(t, int64(19), syntaxError.Offset)
+	assert.Equal(t, 3, syntaxError.Line)
+	assert.Equal(t, 7, syntaxError.Column)
+	assert.Equal(t, byte('3'), syntaxError.Byte)
+	assert.Equal(t, 2, syntaxError.Segment)
+	assert.Equal(t, " 3]}", syntaxError.Excerpt)
+	assert.Equal(t, "unexpected token symbol `3` in json stream at line 3, column 7 (offset 19, segment 2), near ` 3]}`", syntaxError.Error())
+}
+
+func TestSyntaxError_invalidQuote(t *testing.T) {
+	lexer := NewLexer()
+	assert.Nil(t, lexer.AppendString(`{"a":1`))
+	errInAppendString := lexer.AppendString(` "b"`)
+
+	var syntaxError *SyntaxError
+	if !assert.True(t, errors.As(errInAppendString, &syntaxError)) {
+		return
+	}
+	assert.Equal(t, int64(7), syntaxError.Offset)
+	assert.Equal(t, 1, syntaxError.Line)
+	assert.Equal(t, 8, syntaxError.Column)
+	assert.Equal(t, byte('"'), syntaxError.Byte)
+	assert.Equal(t, 1, syntaxError.Segment)
+}
+
+func TestSyntaxError_excerpt(t *testing.T) {
+	lexer := NewLexer(WithStrict())
+	errInAppendString := lexer.AppendString(`{"aaaaaaaaaaaaaaaaaaaaaaaaa":xbbbbbbbbbbbbbbbbbbbbbbbbbbbbb}`)
+
+	var syntaxError *SyntaxError
+	if !assert.True(t, errors.As(errInAppendString, &syntaxError)) {
+		return
+	}
+	assert.Equal(t, `aaaaaaaaaaaaaa":xbbbbbbbbbbbbbbbb`, syntaxError.Excerpt)
+}
@@ -0,0 +1,121 @@
+package streamingjsongo
+
+// embedded JSON extractor state const
+const (
+	extractStateSearch = iota // skipping the text before JSON, like `Here is the result:`
+	extractStateFence         // skipping the info string of the opening code fence, like "```json"
+	extractStateBody          // in the JSON body
+	extractStateDone          // the JSON body ended, the rest of the stream is skipped
+)
+
+// length of the backtick run of Markdown code fence
+const codeFenceLength = 3
+
+// embedded JSON extractor skips the text around the JSON body, the body starts after the opening code fence line,
+// or at the first `{` or `[`, and ends at the closing code fence, or where the top-level value completed
+type jsonExtractor struct {
+	state     int  // current extractor state
+	fenced    bool // the body is in a code fence
+	backticks int  // count of consecutive backticks before the body
+}
+
+// reset extractor for a new JSON stream
+func (extractor *jsonExtractor) reset() {
+	extractor.state = extractStateSearch
+	extractor.fenced = false
+	extractor.backticks = 0
+}
+
+// check if the byte belongs to the JSON body, the bytes out of the body are skipped
+func (lexer *Lexer) extractByte(c byte) bool {
+	extractor := &lexer.extractor
+	switch extractor.state {
+	case extractStateSearch:
+		switch c {
+		case '`':
+			extractor.backticks++
+			if extractor.backticks == codeFenceLength {
+				extractor.backticks = 0
+				extractor.state = extractStateFence
+			}
+			return false
+		case TOKEN_LEFT_BRACE_SYMBOL, TOKEN_LEFT_BRACKET_SYMBOL:
+			extractor.backticks = 0
+			extractor.fenced = false
+			extractor.state = extractStateBody
+			return true
+		}
+		extractor.backticks = 0
+		return false
+	case extractStateFence:
+		switch c {
+		case '\n':
+			extractor.fenced = true
+			extractor.state = extractStateBody
+			return false
+		case TOKEN_LEFT_BRACE_SYMBOL, TOKEN_LEFT_BRACKET_SYMBOL:
+			// the body follows the opening code fence in the same line, like "```{"
+			extractor.fenced = true
+			extractor.state = extractStateBody
+			return true
+		}
+		return false
+	case extractStateBody:
+		// a backtick out of strings starts the closing code fence
+		if c == '`' && extractor.fenced && !lexer.streamStoppedInAStringOrEscape() {
+			lexer.endExtractedBody()
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// the top-level value in the body completed
+func (lexer *Lexer) endExtractedValue() {
+	// more documents may follow in the code fence
+	if lexer.options.multiDocument && lexer.extractor.fenced {
+		return
+	}
+	lexer.endExtractedBody()
+}
+
+// the body ended, search the next body for the next document in multiple documents mode
+func (lexer *Lexer) endExtractedBody() {
+	if lexer.options.multiDocument {
+		lexer.extractor.reset()
+		return
+	}
+	lexer.extractor.state = extractStateDone
+}
+
+// check if the body is a false start in text, like `{tool}` or `[nope]`, the unfenced body is rejected by grammar
+// before anything but open containers, whitespace and the partial literal in lexing is lexed
+func (lexer *Lexer) falseBodyStart() bool {
+	if lexer.extractor.state != extractStateBody || lexer.extractor.fenced {
+		return false
+	}
+	content := lexer.JSONContent.Bytes()
+	if lexer.grammar.state == grammarStateLiteral {
+		content = content[:lexer.grammar.scalarStart]
+	}
+	for _, c := range content {
+		switch c {
+		case TOKEN_LEFT_BRACE_SYMBOL, TOKEN_LEFT_BRACKET_SYMBOL, ' ', '\t', '\n', '\r':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// drop the false start of body and search the body again from the byte rejecting it
+func (lexer *Lexer) restartExtraction(c byte, position streamPosition) *SyntaxError {
+	lexer.resetDocument()
+	lexer.resetStages()
+	lexer.extractor.reset()
+	if !lexer.extractByte(c) {
+		return nil
+	}
+	return lexer.lexByte(c, position)
+}
@@ -0,0 +1,63 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithExtractEmbedded(t *testing.T) {
+	streamingJSONCase := map[string]string{
+		"Here is the result:\n```json\n{\"a\":\"x`y\",\"b\":[1,2]}\n```\nHope it helps {}": "{\"a\":\"x`y\",\"b\":[1,2]}",
+		"Sure! {\"a\":1} and [more] text":                                                  `{"a":1}`,
+		"```json\n{\"a\":[tr":                                                              `{"a":[true]}`,
+		"```{\"a\":1}```":                                                                  `{"a":1}`,
+		"```json\n42\n```":                                                                 `42`,
+		"```json\n\"a\"```":                                                                `"a"`,
+		"Here is the result:\n``":                                                          ``,
+		"no JSON at all":                                                                   ``,
+		"I'll call {tool} now:\n```json\n{\"a\":1}\n```":                                   `{"a":1}`,
+		"Result [see below]:\n```json\n{\"a\":[1]}```":                                     `{"a":[1]}`,
+		"Use {x} or [ x ] then {{\"a\":1}":                                                 `{"a":1}`,
+		"Maybe [[nope]] {\"a\":2":                                                          `{"a":2}`,
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer(WithExtractEmbedded())
+		for i := 0; i < len(testCase); i++ {
+			assert.Nil(t, lexer.AppendString(testCase[i:i+1]))
+			completedJSON := lexer.CompleteJSON()
+			if completedJSON != `` {
+				assert.True(t, json.Valid([]byte(completedJSON)), "invalid completion %s of: %q", completedJSON, testCase[:i+1])
+			}
+		}
+		assert.Equal(t, expect, lexer.CompleteJSON(), "unexpected completion in case: %q", testCase)
+	}
+}
+
+func TestWithExtractEmbedded_multipleDocuments(t *testing.T) {
+	lexer := NewLexer(WithExtractEmbedded(), WithMultipleDocuments())
+	assert.Nil(t, lexer.AppendString("First: {\"a\":1}\nThen:\n```json\n{\"b\":2}\n{\"c\":3}\n```\nAnd [4"))
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{"a":1}`), json.RawMessage(`{"b":2}`), json.RawMessage(`{"c":3}`)}, lexer.Documents())
+	assert.Equal(t, `[4]`, lexer.CompleteJSON())
+}
+
+func TestWithExtractEmbedded_strict(t *testing.T) {
+	lexer := NewLexer(WithExtractEmbedded(), WithStrict())
+	err := lexer.AppendString(`text {"a"]`)
+	assert.NotNil(t, err)
+	syntaxError, ok := err.(*SyntaxError)
+	assert.True(t, ok)
+	assert.Equal(t, int64(9), syntaxError.Offset)
+
+	lexer.Reset()
+	assert.Nil(t, lexer.AppendString(`{"a":1}`))
+	assert.Equal(t, `{"a":1}`, lexer.CompleteJSON())
+
+	// braces in the text before JSON are not rejected
+	lexer.Reset()
+	assert.Nil(t, lexer.AppendString("I'll call {tool} now:\n```json\n{\"a\":1}\n```"))
+	value, err := lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": json.Number("1")}, value)
+}
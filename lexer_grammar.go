@@ -0,0 +1,430 @@
+package streamingjsongo
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// grammar state const, describes what the grammar expects for the next byte
+const (
+	grammarStateValue           = iota // expecting a value, like `{"a":`
+	grammarStateArrayValueOrEnd        // expecting a value or `]`, like `[`
+	grammarStateObjectKeyOrEnd         // expecting a key or `}`, like `{`
+	grammarStateObjectKey              // expecting a key, like `{"a":1,`
+	grammarStateObjectColon            // expecting `:`, like `{"a"`
+	grammarStateAfterValue             // expecting `,` or a closing token, like `[1`
+	grammarStateString                 // in a string, like `"abc`
+	grammarStateStringEscape           // after escape character in a string, like `"\`
+	grammarStateStringUnicode          // in a unicode escape of a string, like `"\u00`
+	grammarStateNumber                 // in a number, like `-12.5e`, the number lexer lexes it
+	grammarStateLiteral                // in a literal, like `tr`
+	grammarStateDone                   // top-level value finished, like `{}`
+)
+
+// grammar listener is notified when the grammar meets a JSON structure or value
+type grammarListener interface {
+	onObjectStart()
+	onObjectEnd()
+	onArrayStart()
+	onArrayEnd()
+	onStringStart(isKey bool)
+	onStringByte(c byte) // decoded byte of string content, escapes are already decoded
+	onStringEnd(isKey bool)
+	onNumberStart()
+	onNumberByte(c byte)
+	onNumberEnd()
+	onLiteralStart(literal string) // the first byte of literal arrived, the literal is determined by it
+	onLiteralEnd()
+}
+
+// grammar tracks the JSON grammar of the stream byte by byte, it is used for validating the stream
+// and notifying the listeners
+type grammar struct {
+	state        int               // current grammar state
+	containers   []int             // open containers, TOKEN_LEFT_BRACE or TOKEN_LEFT_BRACKET
+	memberCuts   []int             // offsets to cut the member in lexing off each open container, after its last complete member
+	inKey        bool              // current string is an object key
+	unicodeLeft  int               // hex digits left in current unicode escape
+	unicodeValue rune              // decoded value of current unicode escape
+	surrogate    rune              // high surrogate waiting for its low surrogate, 0 if none
+	runeBytes    [utf8.UTFMax]byte // incomplete multi-byte UTF-8 sequence in current string
+	runeLen      int               // length of the incomplete UTF-8 sequence
+	number       numberLexer       // lexer of current number
+	literal      string            // literal in matching, like `true`
+	literalIndex int               // matched length of literal
+	listeners    []grammarListener // notified listeners
+	offset       int               // count of fed bytes, it is the offset of current byte in JSON content while notifying
+	scalarStart  int               // offset of the string value, number or literal in lexing
+}
+
+// reset grammar for a new JSON stream
+func (g *grammar) reset() {
+	g.state = grammarStateValue
+	g.containers = g.containers[:0]
+	g.memberCuts = g.memberCuts[:0]
+	g.inKey = false
+	g.unicodeLeft = 0
+	g.unicodeValue = 0
+	g.surrogate = 0
+	g.runeLen = 0
+	g.number.end()
+	g.literal = ""
+	g.literalIndex = 0
+	g.offset = 0
+	g.scalarStart = 0
+}
+
+// get open container on the top of grammar
+func (g *grammar) topContainer() int {
+	containersLen := len(g.containers)
+	if containersLen == 0 {
+		return TOKEN_EOF
+	}
+	return g.containers[containersLen-1]
+}
+
+// check if a string or key is in lexing
+func (g *grammar) inString() bool {
+	return g.state >= grammarStateString && g.state <= grammarStateStringUnicode
+}
+
+// check if a number is in lexing
+func (g *grammar) inNumber() bool {
+	return g.state == grammarStateNumber
+}
+
+// check if a number or literal is in lexing
+func (g *grammar) inNumberOrLiteral() bool {
+	return g.state == grammarStateNumber || g.state == grammarStateLiteral
+}
+
+// finish current value at given end offset, the grammar expects `,` or a closing token after it,
+// or nothing but ignored tokens if the top-level value finished
+func (g *grammar) endValue(end int) {
+	containersLen := len(g.containers)
+	if containersLen == 0 {
+		g.state = grammarStateDone
+		return
+	}
+	g.memberCuts[containersLen-1] = end
+	g.state = grammarStateAfterValue
+}
+
+// open a container at current byte
+func (g *grammar) openContainer(token int) {
+	g.containers = append(g.containers, token)
+	g.memberCuts = append(g.memberCuts, g.offset+1)
+}
+
+// close the container on the top of grammar, the container must match the given opening token
+func (g *grammar) closeContainer(token int) bool {
+	if g.topContainer() != token {
+		return false
+	}
+	g.containers = g.containers[:len(g.containers)-1]
+	g.memberCuts = g.memberCuts[:len(g.memberCuts)-1]
+	g.endValue(g.offset + 1)
+	for _, listener := range g.listeners {
+		if token == TOKEN_LEFT_BRACE {
+			listener.onObjectEnd()
+		} else {
+			listener.onArrayEnd()
+		}
+	}
+	return true
+}
+
+// start a value by given first byte
+func (g *grammar) startValue(c byte) bool {
+	switch c {
+	case TOKEN_LEFT_BRACE_SYMBOL:
+		g.openContainer(TOKEN_LEFT_BRACE)
+		g.state = grammarStateObjectKeyOrEnd
+		for _, listener := range g.listeners {
+			listener.onObjectStart()
+		}
+	case TOKEN_LEFT_BRACKET_SYMBOL:
+		g.openContainer(TOKEN_LEFT_BRACKET)
+		g.state = grammarStateArrayValueOrEnd
+		for _, listener := range g.listeners {
+			listener.onArrayStart()
+		}
+	case TOKEN_QUOTE_SYMBOL:
+		g.startString(false)
+	case TOKEN_NEGATIVE_SYMBOL:
+		g.startNumber(c)
+	case TOKEN_ALPHABET_LOWERCASE_T_SYMBOL:
+		g.startLiteral(tokenSymbolMap[TOKEN_TRUE])
+	case TOKEN_ALPHABET_LOWERCASE_F_SYMBOL:
+		g.startLiteral(tokenSymbolMap[TOKEN_FLASE])
+	case TOKEN_ALPHABET_LOWERCASE_N_SYMBOL:
+		g.startLiteral(tokenSymbolMap[TOKEN_NULL])
+	default:
+		if !isDigit(c) {
+			return false
+		}
+		g.startNumber(c)
+	}
+	return true
+}
+
+// start a string, the opening quote is already matched
+func (g *grammar) startString(isKey bool) {
+	g.inKey = isKey
+	g.state = grammarStateString
+	if !isKey {
+		g.scalarStart = g.offset
+	}
+	for _, listener := range g.listeners {
+		listener.onStringStart(isKey)
+	}
+}
+
+// write decoded byte into current string
+func (g *grammar) stringByte(c byte) {
+	for _, listener := range g.listeners {
+		listener.onStringByte(c)
+	}
+}
+
+// write decoded rune of unicode escape into current string
+func (g *grammar) stringRune(r rune) {
+	var encoded [utf8.UTFMax]byte
+	encodedLen := utf8.EncodeRune(encoded[:], r)
+	for i := 0; i < encodedLen; i++ {
+		g.stringByte(encoded[i])
+	}
+}
+
+// write byte of multi-byte UTF-8 sequence into current string, the sequence is written once it is complete,
+// so listeners never get a part of a character
+func (g *grammar) runeByte(c byte) {
+	g.runeBytes[g.runeLen] = c
+	g.runeLen++
+	if utf8.FullRune(g.runeBytes[:g.runeLen]) {
+		g.flushRuneBytes()
+	}
+}
+
+// write the buffered UTF-8 sequence into current string
+func (g *grammar) flushRuneBytes() {
+	for _, c := range g.runeBytes[:g.runeLen] {
+		g.stringByte(c)
+	}
+	g.runeLen = 0
+}
+
+// write the rune of unicode escape into current string, a high surrogate waits for its low surrogate
+func (g *grammar) unicodeRune(value rune) {
+	if g.surrogate != 0 && isLowSurrogate(value) {
+		g.stringRune(utf16.DecodeRune(g.surrogate, value))
+		g.surrogate = 0
+		return
+	}
+	g.flushSurrogate()
+	if isHighSurrogate(value) {
+		g.surrogate = value
+		return
+	}
+	// a lone low surrogate is encoded as replacement character
+	g.stringRune(value)
+}
+
+// write the high surrogate not followed by its low surrogate into current string as replacement character
+func (g *grammar) flushSurrogate() {
+	if g.surrogate != 0 {
+		g.stringRune(utf8.RuneError)
+		g.surrogate = 0
+	}
+}
+
+// start a number by given first byte
+func (g *grammar) startNumber(c byte) {
+	g.state = grammarStateNumber
+	g.number.begin(c, g.offset)
+	g.scalarStart = g.offset
+	for _, listener := range g.listeners {
+		listener.onNumberStart()
+		listener.onNumberByte(c)
+	}
+}
+
+// write byte into current number
+func (g *grammar) numberByte(c byte) {
+	for _, listener := range g.listeners {
+		listener.onNumberByte(c)
+	}
+}
+
+// start a literal, the first byte of literal is already matched
+func (g *grammar) startLiteral(literal string) {
+	g.literal = literal
+	g.literalIndex = 1
+	g.state = grammarStateLiteral
+	g.scalarStart = g.offset
+	for _, listener := range g.listeners {
+		listener.onLiteralStart(literal)
+	}
+}
+
+// finish current number by given byte following it, then feed the byte again
+func (g *grammar) endNumber(c byte) bool {
+	g.number.end()
+	g.endValue(g.offset)
+	for _, listener := range g.listeners {
+		listener.onNumberEnd()
+	}
+	return g.step(c)
+}
+
+// feed byte into grammar, returns false if the byte can never be part of a valid JSON
+func (g *grammar) feed(c byte) bool {
+	ok := g.step(c)
+	g.offset++
+	return ok
+}
+
+// move grammar state by given byte
+func (g *grammar) step(c byte) bool {
+	switch g.state {
+	case grammarStateValue:
+		if isIgnoreToken(c) {
+			return true
+		}
+		return g.startValue(c)
+	case grammarStateArrayValueOrEnd:
+		if isIgnoreToken(c) {
+			return true
+		}
+		if c == TOKEN_RIGHT_BRACKET_SYMBOL {
+			return g.closeContainer(TOKEN_LEFT_BRACKET)
+		}
+		return g.startValue(c)
+	case grammarStateObjectKeyOrEnd, grammarStateObjectKey:
+		if isIgnoreToken(c) {
+			return true
+		}
+		if c == TOKEN_RIGHT_BRACE_SYMBOL && g.state == grammarStateObjectKeyOrEnd {
+			return g.closeContainer(TOKEN_LEFT_BRACE)
+		}
+		if c != TOKEN_QUOTE_SYMBOL {
+			return false
+		}
+		g.startString(true)
+	case grammarStateObjectColon:
+		if isIgnoreToken(c) {
+			return true
+		}
+		if c != TOKEN_COLON_SYMBOL {
+			return false
+		}
+		g.state = grammarStateValue
+	case grammarStateAfterValue:
+		if isIgnoreToken(c) {
+			return true
+		}
+		switch c {
+		case TOKEN_COMMA_SYMBOL:
+			if g.topContainer() == TOKEN_LEFT_BRACE {
+				g.state = grammarStateObjectKey
+			} else {
+				g.state = grammarStateValue
+			}
+		case TOKEN_RIGHT_BRACKET_SYMBOL:
+			return g.closeContainer(TOKEN_LEFT_BRACKET)
+		case TOKEN_RIGHT_BRACE_SYMBOL:
+			return g.closeContainer(TOKEN_LEFT_BRACE)
+		default:
+			return false
+		}
+	case grammarStateString:
+		if c != TOKEN_ESCAPE_CHARACTER_SYMBOL {
+			g.flushSurrogate()
+		}
+		if g.runeLen > 0 && !isRuneContinuation(c) {
+			// the sequence is truncated in an invalid stream
+			g.flushRuneBytes()
+		}
+		switch {
+		case c == TOKEN_QUOTE_SYMBOL:
+			isKey := g.inKey
+			if isKey {
+				g.inKey = false
+				g.state = grammarStateObjectColon
+			} else {
+				g.endValue(g.offset + 1)
+			}
+			for _, listener := range g.listeners {
+				listener.onStringEnd(isKey)
+			}
+		case c == TOKEN_ESCAPE_CHARACTER_SYMBOL:
+			g.state = grammarStateStringEscape
+		case c < 0x20:
+			// control characters must be escaped in a string
+			return false
+		case isRuneStart(c) || g.runeLen > 0:
+			g.runeByte(c)
+		default:
+			g.stringByte(c)
+		}
+	case grammarStateStringEscape:
+		if c != TOKEN_ALPHABET_LOWERCASE_U_SYMBOL {
+			g.flushSurrogate()
+		}
+		switch c {
+		case TOKEN_QUOTE_SYMBOL, TOKEN_ESCAPE_CHARACTER_SYMBOL, TOKEN_SLASH_SYMBOL:
+			g.stringByte(c)
+		case 'b':
+			g.stringByte('\b')
+		case 'f':
+			g.stringByte('\f')
+		case 'n':
+			g.stringByte('\n')
+		case 'r':
+			g.stringByte('\r')
+		case 't':
+			g.stringByte('\t')
+		case TOKEN_ALPHABET_LOWERCASE_U_SYMBOL:
+			g.unicodeLeft = 4
+			g.unicodeValue = 0
+			g.state = grammarStateStringUnicode
+			return true
+		default:
+			return false
+		}
+		g.state = grammarStateString
+	case grammarStateStringUnicode:
+		if !isHexDigit(c) {
+			return false
+		}
+		g.unicodeValue = g.unicodeValue<<4 | rune(hexDigitValue(c))
+		g.unicodeLeft--
+		if g.unicodeLeft == 0 {
+			g.unicodeRune(g.unicodeValue)
+			g.state = grammarStateString
+		}
+	case grammarStateNumber:
+		switch g.number.next(c) {
+		case numberAccepted:
+			g.numberByte(c)
+		case numberEnded:
+			return g.endNumber(c)
+		default:
+			return false
+		}
+	case grammarStateLiteral:
+		if c != g.literal[g.literalIndex] {
+			return false
+		}
+		g.literalIndex++
+		if g.literalIndex == len(g.literal) {
+			g.endValue(g.offset + 1)
+			for _, listener := range g.listeners {
+				listener.onLiteralEnd()
+			}
+		}
+	case grammarStateDone:
+		return isIgnoreToken(c)
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package streamingjsongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendString_strictValidPrefix(t *testing.T) {
+	streamingJSONCase := []string{
+		`{`,
+		`{"a":`,
+		`{"a":-`,
+		`{"a":-0.`,
+		`{"a":-1.215e`,
+		`{"a":-1.215E+`,
+		`{"a":[tr`,
+		`{"a":"\u00`,
+		`{"a":"\"\\\/\b\f\n\r\t`,
+		`{"a":{"b":[1, 2.5, true, false, null, "c", {}, []]}, "d": 0}`,
+		`[ 1 , -1.020  , true ,  false,  null,  {   }`,
+		`"string`,
+		`"string"`,
+		`-12.5e3`,
+		`true `,
+	}
+	for _, testCase := range streamingJSONCase {
+		lexer := NewLexer(WithStrict())
+		for i := 0; i < len(testCase); i++ {
+			errInAppendString := lexer.AppendString(testCase[i : i+1])
+			if !assert.Nil(t, errInAppendString, "unexpected error in case: %s", testCase) {
+				break
+			}
+		}
+	}
+}
+
+func TestAppendString_strictInvalid(t *testing.T) {
+	// the key is the invalid JSON stream, the value is the valid prefix before the invalid byte
+	streamingJSONCase := map[string]string{
+		`{"a":xyz`:         `{"a":`,
+		`[1 2]`:            `[1 `,
+		`{"a" 1}`:          `{"a" `,
+		`{1:2}`:            `{`,
+		`{"a":1,}`:         `{"a":1,`,
+		`[1,]`:             `[1,`,
+		`[}`:               `[`,
+		`{]`:               `{`,
+		`{"a":tx`:          `{"a":t`,
+		`{"a":01`:          `{"a":0`,
+		`{"a":1.2.3`:       `{"a":1.2`,
+		`{"a":1.e`:         `{"a":1.`,
+		`{"a":-a`:          `{"a":-`,
+		`{"a":"\x"`:        `{"a":"\`,
+		`{"a":"\u12g4"`:    `{"a":"\u12`,
+		"{\"a\":\"\n\"}":   `{"a":"`,
+		`{}{}`:             `{}`,
+		`[1]]`:             `[1]`,
+		`{"a":[true],"b"}`: `{"a":[true],"b"`,
+	}
+	for testCase, validPrefix := range streamingJSONCase {
+		lexer := NewLexer(WithStrict())
+		assert.Nil(t, lexer.AppendString(validPrefix), "unexpected error in case: %s", testCase)
+		assert.NotNil(t, lexer.AppendString(testCase[len(validPrefix):]), "expect error in case: %s", testCase)
+	}
+}
+
+func TestCompleteJSON_strict(t *testing.T) {
+	streamingJSONCase := map[string]string{
+		`{"a":[tr`:   `{"a":[true]}`,
+		`{"a":-1.2`:  `{"a":-1.2}`,
+		`"string`:    `"string"`,
+		`"str\"ing"`: `"str\"ing"`,
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer(WithStrict())
+		errInAppendString := lexer.AppendString(testCase)
+		ret := lexer.CompleteJSON()
+		assert.Nil(t, errInAppendString)
+		assert.Equal(t, expect, ret, "unexpected JSON")
+	}
+}
@@ -0,0 +1,150 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+)
+
+// Handler is notified by events of JSON structures as they arrive, set it by WithHandler().
+// events are fired in the order of JSON stream, the values are already decoded.
+// an event is fired while the byte finishing it is lexed, before the byte is written to JSONContent,
+// except string fragments notified at the end of each appended segment.
+// after the first grammar error in lenient mode, OnError() is the last event of the JSON stream
+type Handler interface {
+	// an object starts, like `{`
+	OnObjectStart()
+	// a key of object member is complete, like `"a"` in `{"a":`
+	OnKey(key string)
+	// a fragment of string value arrived, the fragments are notified at the end of each appended segment,
+	// end is true on the last fragment of the string, the last fragment can be empty
+	OnStringFragment(fragment string, end bool)
+	// a number, `true`, `false` or `null` is complete, numbers are json.Number.
+	// a top-level number is not complete until a byte after it arrived
+	OnScalar(value interface{})
+	// the innermost object ends, like `}`
+	OnObjectEnd()
+	// an array starts, like `[`
+	OnArrayStart()
+	// the innermost array ends, like `]`
+	OnArrayEnd()
+	// the document in lexing is broken by the grammar error, the events of it must be discarded.
+	// no more events follow in lenient mode, unless the document is dropped by WithMultipleDocuments()
+	// or the text like `{tool}` is skipped by WithExtractEmbedded(), then the events of the next document follow
+	OnError(err *SyntaxError)
+}
+
+// handler adapter notifies the Handler by grammar notifications, it buffers strings and numbers until they can be notified
+type handlerAdapter struct {
+	handler  Handler
+	str      []byte // decoded bytes of the key or string value not notified yet
+	inString bool   // a string value is in lexing
+	number   []byte // content of the number in lexing
+	literal  string // literal in lexing
+}
+
+// reset adapter with given handler
+func (adapter *handlerAdapter) reset(handler Handler) {
+	adapter.handler = handler
+	adapter.str = adapter.str[:0]
+	adapter.inString = false
+	adapter.number = adapter.number[:0]
+	adapter.literal = ""
+}
+
+// notify the fragment of string value received in current segment
+func (adapter *handlerAdapter) flush() {
+	if adapter.inString && len(adapter.str) > 0 {
+		adapter.handler.OnStringFragment(string(adapter.str), false)
+		adapter.str = adapter.str[:0]
+	}
+}
+
+func (adapter *handlerAdapter) onObjectStart() {
+	adapter.handler.OnObjectStart()
+}
+
+func (adapter *handlerAdapter) onObjectEnd() {
+	adapter.handler.OnObjectEnd()
+}
+
+func (adapter *handlerAdapter) onArrayStart() {
+	adapter.handler.OnArrayStart()
+}
+
+func (adapter *handlerAdapter) onArrayEnd() {
+	adapter.handler.OnArrayEnd()
+}
+
+func (adapter *handlerAdapter) onStringStart(isKey bool) {
+	adapter.inString = !isKey
+	adapter.str = adapter.str[:0]
+}
+
+func (adapter *handlerAdapter) onStringByte(c byte) {
+	adapter.str = append(adapter.str, c)
+}
+
+func (adapter *handlerAdapter) onStringEnd(isKey bool) {
+	if isKey {
+		adapter.handler.OnKey(string(adapter.str))
+	} else {
+		adapter.handler.OnStringFragment(string(adapter.str), true)
+	}
+	adapter.inString = false
+	adapter.str = adapter.str[:0]
+}
+
+func (adapter *handlerAdapter) onNumberStart() {
+	adapter.number = adapter.number[:0]
+}
+
+func (adapter *handlerAdapter) onNumberByte(c byte) {
+	adapter.number = append(adapter.number, c)
+}
+
+func (adapter *handlerAdapter) onNumberEnd() {
+	adapter.handler.OnScalar(json.Number(string(adapter.number)))
+}
+
+func (adapter *handlerAdapter) onLiteralStart(literal string) {
+	adapter.literal = literal
+}
+
+func (adapter *handlerAdapter) onLiteralEnd() {
+	switch adapter.literal {
+	case tokenSymbolMap[TOKEN_TRUE]:
+		adapter.handler.OnScalar(true)
+	case tokenSymbolMap[TOKEN_FLASE]:
+		adapter.handler.OnScalar(false)
+	default:
+		adapter.handler.OnScalar(nil)
+	}
+}
+
+// reset grammar listeners to the handler given by options and the subscriptions,
+// the value builder is attached by Value() later, or now if OnUpdate() subscriptions or atomic paths need it
+func (lexer *Lexer) resetListeners() {
+	lexer.grammar.listeners = lexer.grammar.listeners[:0]
+	if lexer.options.handler != nil {
+		lexer.handler.reset(lexer.options.handler)
+		lexer.grammar.listeners = append(lexer.grammar.listeners, &lexer.handler)
+	}
+	needValue := len(lexer.atomicPaths) > 0
+	if lexer.paths != nil {
+		lexer.paths.reset()
+		lexer.grammar.listeners = append(lexer.grammar.listeners, lexer.paths)
+		for _, subscription := range lexer.paths.subscriptions {
+			needValue = needValue || subscription.onUpdate != nil
+		}
+	}
+	if needValue {
+		lexer.value = lexer.newValueBuilder()
+		lexer.grammar.listeners = append(lexer.grammar.listeners, lexer.value)
+	}
+}
+
+// notify the handler that the document in lexing is broken
+func (lexer *Lexer) notifyError(err *SyntaxError) {
+	if lexer.options.handler != nil {
+		lexer.options.handler.OnError(err)
+	}
+}
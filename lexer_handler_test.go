@@ -0,0 +1,106 @@
+package streamingjsongo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// handler records events as strings
+type testRecordingHandler struct {
+	events []string
+}
+
+func (handler *testRecordingHandler) OnObjectStart() {
+	handler.events = append(handler.events, "{")
+}
+
+func (handler *testRecordingHandler) OnKey(key string) {
+	handler.events = append(handler.events, "key:"+key)
+}
+
+func (handler *testRecordingHandler) OnStringFragment(fragment string, end bool) {
+	handler.events = append(handler.events, fmt.Sprintf("fragment:%s:%t", fragment, end))
+}
+
+func (handler *testRecordingHandler) OnScalar(value interface{}) {
+	handler.events = append(handler.events, fmt.Sprintf("scalar:%v", value))
+}
+
+func (handler *testRecordingHandler) OnObjectEnd() {
+	handler.events = append(handler.events, "}")
+}
+
+func (handler *testRecordingHandler) OnArrayStart() {
+	handler.events = append(handler.events, "[")
+}
+
+func (handler *testRecordingHandler) OnArrayEnd() {
+	handler.events = append(handler.events, "]")
+}
+
+func (handler *testRecordingHandler) OnError(err *SyntaxError) {
+	handler.events = append(handler.events, fmt.Sprintf("error:%d", err.Offset))
+}
+
+func TestHandler(t *testing.T) {
+	handler := &testRecordingHandler{}
+	lexer := NewLexer(WithHandler(handler))
+	segments := []string{`{"a":[1, -2.5e3, tr`, `ue, false, null], "b`, `c": "xé`, `\nyz", "d":{}}`}
+	for _, segment := range segments {
+		assert.Nil(t, lexer.AppendString(segment))
+	}
+	expect := []string{
+		"{", "key:a", "[", "scalar:1", "scalar:-2.5e3", "scalar:true", "scalar:false", "scalar:<nil>", "]",
+		"key:bc", "fragment:xé:false", "fragment:\nyz:true",
+		"key:d", "{", "}", "}",
+	}
+	assert.Equal(t, expect, handler.events)
+}
+
+func TestHandler_withValue(t *testing.T) {
+	handler := &testRecordingHandler{}
+	lexer := NewLexer(WithHandler(handler))
+	assert.Nil(t, lexer.AppendString(`["a", `))
+
+	// the value builder is attached later, the handler is not notified twice
+	value, err := lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"a"}, value)
+	assert.Nil(t, lexer.AppendString(`"b"]`))
+	assert.Equal(t, []string{"[", "fragment:a:true", "fragment:b:true", "]"}, handler.events)
+
+	// the handler is kept after reset
+	lexer.Reset()
+	assert.Nil(t, lexer.AppendString(`{}`))
+	assert.Equal(t, []string{"[", "fragment:a:true", "fragment:b:true", "]", "{", "}"}, handler.events)
+}
+
+func TestHandler_error(t *testing.T) {
+	// lenient mode, no events follow the error
+	handler := &testRecordingHandler{}
+	lexer := NewLexer(WithHandler(handler))
+	assert.Nil(t, lexer.AppendString(`{"a":[1 2]}`))
+	assert.Nil(t, lexer.AppendString(`{"c":3}`))
+	assert.Equal(t, []string{"{", "key:a", "[", "scalar:1", "error:8"}, handler.events)
+
+	// strict mode, the byte is rejected without breaking the document
+	handler = &testRecordingHandler{}
+	lexer = NewLexer(WithHandler(handler), WithStrict())
+	assert.NotNil(t, lexer.AppendString(`[1}`))
+	assert.Nil(t, lexer.AppendString(`]`))
+	assert.Equal(t, []string{"[", "scalar:1", "]"}, handler.events)
+
+	// multiple documents mode, the events of the next document follow
+	handler = &testRecordingHandler{}
+	lexer = NewLexer(WithHandler(handler), WithMultipleDocuments())
+	assert.Nil(t, lexer.AppendString("[1 2]\n[3]\n"))
+	assert.Equal(t, []string{"[", "scalar:1", "error:3", "[", "scalar:3", "]"}, handler.events)
+
+	// the text before embedded JSON
+	handler = &testRecordingHandler{}
+	lexer = NewLexer(WithHandler(handler), WithExtractEmbedded())
+	assert.Nil(t, lexer.AppendString(`call {tool} with {"a":true}`))
+	assert.Equal(t, []string{"{", "error:6", "{", "key:a", "scalar:true", "}"}, handler.events)
+}
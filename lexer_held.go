@@ -0,0 +1,68 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// partial value held by a stage before lexing until the following bytes determine it, like `0` of `0x1F`
+type heldValue struct {
+	value  string // JSON value completing it, like `0` of `-`
+	zero   string // zero value of its type for CompleteZero
+	number bool   // it is a number completed by PartialNumber and atomic policies, or a literal completed by PartialLiteral
+}
+
+// get the partial value held by the stages before lexing, returns false if nothing is held
+func (lexer *Lexer) heldValue() (heldValue, bool) {
+	if lexer.grammarError != nil {
+		return heldValue{}, false
+	}
+	if lexer.options.relaxed {
+		if held, ok := lexer.relaxed.heldValue(); ok {
+			return held, true
+		}
+	}
+	if lexer.options.pythonLiterals {
+		return lexer.python.heldValue()
+	}
+	return heldValue{}, false
+}
+
+// get the atomic policy of the held value, only numbers can be atomic
+func (lexer *Lexer) heldAtomicPolicy(held heldValue) CompletionPolicy {
+	if !held.number {
+		return CompleteKeep
+	}
+	if lexer.value != nil {
+		// the held value stored by Value() is not a started value
+		lexer.value.removeHeldValue()
+		return lexer.value.startPolicy()
+	}
+	return lexer.options.atomicPolicy
+}
+
+// complete the held value by policy, it follows the padding content like the lexed value does
+func (lexer *Lexer) completeHeldValue(policy CompletionPolicy, held heldValue, opts *CompletionOptions) completion {
+	switch policy {
+	case CompleteOmit:
+		return lexer.omitMember()
+	case CompleteNull:
+		held.value = tokenSymbolMap[TOKEN_NULL]
+	case CompleteZero:
+		held.value = held.zero
+	case CompleteSentinel:
+		held.value = opts.Sentinel
+	}
+	return completion{cut: lexer.JSONContent.Len(), replacement: lexer.PaddingContent.String() + held.value}
+}
+
+// decode the JSON value of the held value for Value()
+func decodeHeldValue(value string) interface{} {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil
+	}
+	return decoded
+}
@@ -29,3 +29,58 @@ func matchStack(stack []int, tokens []int) bool {
 	}
 	return true
 }
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// value of hex digit, the digit must be checked by isHexDigit() first
+func hexDigitValue(c byte) byte {
+	switch {
+	case isDigit(c):
+		return c - '0'
+	case c >= 'a':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+// length of unicode escape, like `\u00e9`
+const unicodeEscapeLength = 6
+
+// unicode escape of replacement character U+FFFD, lone surrogates are decoded to it
+const replacementCharacterEscape = `\ufffd`
+
+// value of unicode escape, the escape must be `\u` and 4 hex digits
+func unicodeEscapeValue(escape []byte) rune {
+	var value rune
+	for _, c := range escape[2:unicodeEscapeLength] {
+		value = value<<4 | rune(hexDigitValue(c))
+	}
+	return value
+}
+
+// check if the UTF-16 code unit is a high surrogate, which is followed by a low surrogate in a pair
+func isHighSurrogate(value rune) bool {
+	return value >= 0xD800 && value <= 0xDBFF
+}
+
+// check if the UTF-16 code unit is a low surrogate
+func isLowSurrogate(value rune) bool {
+	return value >= 0xDC00 && value <= 0xDFFF
+}
+
+// check if byte starts a multi-byte UTF-8 sequence, like the first byte of `中`
+func isRuneStart(c byte) bool {
+	return c >= 0xC2 && c <= 0xF4
+}
+
+// check if byte continues a multi-byte UTF-8 sequence
+func isRuneContinuation(c byte) bool {
+	return c&0xC0 == 0x80
+}
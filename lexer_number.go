@@ -0,0 +1,136 @@
+package streamingjsongo
+
+// number lexer state const, describes the last part of the number in lexing
+const (
+	numberStateNone           = iota // not in a number
+	numberStateSign                  // after negative symbol, like `-`
+	numberStateZero                  // after leading zero, like `-0`
+	numberStateInteger               // in integer part, like `12`
+	numberStateDot                   // after decimal point, like `12.`
+	numberStateFraction              // in decimal part, like `12.5`
+	numberStateExponent              // after exponent, like `12.5e`
+	numberStateExponentSign          // after exponent sign, like `12.5e-`
+	numberStateExponentDigits        // in exponent digits, like `12.5e-3`
+)
+
+// result of feeding a byte into number lexer
+const (
+	numberAccepted = iota // the byte is part of the number
+	numberEnded           // the byte is not part of the number, and the number is complete before it
+	numberRejected        // the byte can never follow the number, like `1` after `0` or `.` after `1.2`
+)
+
+// number lexer lexes a number byte by byte: sign, integer part, decimal part, exponent sign and exponent digits
+type numberLexer struct {
+	state int // current number lexer state
+	start int // offset of the number in JSON content
+}
+
+// check if byte can be part of a number
+func isNumberByte(c byte) bool {
+	switch c {
+	case TOKEN_NEGATIVE_SYMBOL, '+', TOKEN_DOT_SYMBOL, TOKEN_ALPHABET_LOWERCASE_E_SYMBOL, TOKEN_ALPHABET_UPPERCASE_E_SYMBOL:
+		return true
+	}
+	return isDigit(c)
+}
+
+// check if a number is in lexing
+func (number *numberLexer) open() bool {
+	return number.state != numberStateNone
+}
+
+// start a number at given offset by its first byte, it must be `-` or a digit
+func (number *numberLexer) begin(c byte, start int) {
+	number.start = start
+	switch {
+	case c == TOKEN_NEGATIVE_SYMBOL:
+		number.state = numberStateSign
+	case c == TOKEN_NUMBER_0_SYMBOL:
+		number.state = numberStateZero
+	default:
+		number.state = numberStateInteger
+	}
+}
+
+// finish the number in lexing
+func (number *numberLexer) end() {
+	number.state = numberStateNone
+}
+
+// feed the byte following the number, returns numberAccepted, numberEnded or numberRejected
+func (number *numberLexer) next(c byte) int {
+	if !isNumberByte(c) {
+		switch number.state {
+		case numberStateZero, numberStateInteger, numberStateFraction, numberStateExponentDigits:
+			return numberEnded
+		}
+		return numberRejected
+	}
+	isExponent := c == TOKEN_ALPHABET_LOWERCASE_E_SYMBOL || c == TOKEN_ALPHABET_UPPERCASE_E_SYMBOL
+	switch number.state {
+	case numberStateSign:
+		if c == TOKEN_NUMBER_0_SYMBOL {
+			return number.move(numberStateZero)
+		} else if isDigit(c) {
+			return number.move(numberStateInteger)
+		}
+	case numberStateZero, numberStateInteger:
+		switch {
+		case isDigit(c) && number.state == numberStateInteger:
+			return numberAccepted
+		case c == TOKEN_DOT_SYMBOL:
+			return number.move(numberStateDot)
+		case isExponent:
+			return number.move(numberStateExponent)
+		}
+	case numberStateDot:
+		if isDigit(c) {
+			return number.move(numberStateFraction)
+		}
+	case numberStateFraction:
+		if isDigit(c) {
+			return numberAccepted
+		} else if isExponent {
+			return number.move(numberStateExponent)
+		}
+	case numberStateExponent:
+		if c == '+' || c == TOKEN_NEGATIVE_SYMBOL {
+			return number.move(numberStateExponentSign)
+		} else if isDigit(c) {
+			return number.move(numberStateExponentDigits)
+		}
+	case numberStateExponentSign, numberStateExponentDigits:
+		if isDigit(c) {
+			return number.move(numberStateExponentDigits)
+		}
+	}
+	return numberRejected
+}
+
+// move to given state with the accepted byte
+func (number *numberLexer) move(state int) int {
+	number.state = state
+	return numberAccepted
+}
+
+// complete a partial number and append it to dst, the completed number is valid and keeps the value of the partial one.
+// like `-` completes to `0`, `12.` to `12.0`, `1.5e` to `1.5` and `1.5e-` to `1.5e0`
+func appendCompletedNumber(dst []byte, number []byte) []byte {
+	numberLen := len(number)
+	if numberLen == 0 || (numberLen == 1 && number[0] == TOKEN_NEGATIVE_SYMBOL) {
+		return append(dst, TOKEN_NUMBER_0_SYMBOL)
+	}
+	switch number[numberLen-1] {
+	case TOKEN_DOT_SYMBOL:
+		dst = append(dst, number...)
+		return append(dst, TOKEN_NUMBER_0_SYMBOL)
+	case TOKEN_ALPHABET_LOWERCASE_E_SYMBOL, TOKEN_ALPHABET_UPPERCASE_E_SYMBOL:
+		return append(dst, number[:numberLen-1]...)
+	case TOKEN_NEGATIVE_SYMBOL, '+':
+		// drop the exponent sign, the exponent is zero
+		dst = append(dst, number[:numberLen-1]...)
+		return append(dst, TOKEN_NUMBER_0_SYMBOL)
+	}
+	return append(dst, number...)
+}
@@ -0,0 +1,69 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompleteJSON_number(t *testing.T) {
+	streamingJSONCase := map[string]string{
+		`-`:         `0`,
+		`-0`:        `-0`,
+		`-0.`:       `-0.0`,
+		`-0.5`:      `-0.5`,
+		`0`:         `0`,
+		`12`:        `12`,
+		`12.`:       `12.0`,
+		`12.5`:      `12.5`,
+		`12.5e`:     `12.5`,
+		`12.5E`:     `12.5`,
+		`12.5e-`:    `12.5e0`,
+		`12.5e+`:    `12.5e0`,
+		`12.5E+3`:   `12.5E+3`,
+		`-1e`:       `-1`,
+		`-1e-3`:     `-1e-3`,
+		`[-`:        `[0]`,
+		`[-0`:       `[-0]`,
+		`[1.2e+`:    `[1.2e0]`,
+		`[1.2e+1,`:  `[1.2e+1]`,
+		`{"a":-1E+`: `{"a":-1E0}`,
+		`{"a":0.`:   `{"a":0.0}`,
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer()
+		assert.Nil(t, lexer.AppendString(testCase))
+		assert.Equal(t, expect, lexer.CompleteJSON(), "unexpected completion in case: %s", testCase)
+	}
+}
+
+func TestCompleteJSON_numberPrefixes(t *testing.T) {
+	numbers := []string{`0`, `-0`, `-0.0`, `123`, `-123.456`, `1.5e10`, `-1.5E-10`, `2e+3`, `-0.000e-0`}
+	for _, number := range numbers {
+		for i := 1; i <= len(number); i++ {
+			for _, prefix := range []string{``, `[`, `{"a":`, `[1, `} {
+				lexer := NewLexer(WithStrict())
+				assert.Nil(t, lexer.AppendString(prefix+number[:i]))
+				completedJSON := lexer.CompleteJSON()
+				assert.True(t, json.Valid([]byte(completedJSON)), "invalid completion %s of: %s", completedJSON, number[:i])
+				value, err := lexer.Value()
+				assert.Nil(t, err)
+				assert.Equal(t, decodeCompletedJSON(t, completedJSON), value, "unexpected value of: %s", number[:i])
+			}
+		}
+	}
+}
+
+func TestWithStrict_number(t *testing.T) {
+	invalidJSONCase := []string{`01`, `-01`, `1.2.3`, `-a`, `--1`, `1.e`, `1.,`, `1e+-`, `1ee`, `1e.5`, `[-]`, `{"a":-}`, `[.5]`, `[+1]`, `[1.]`}
+	for _, testCase := range invalidJSONCase {
+		lexer := NewLexer(WithStrict())
+		assert.NotNil(t, lexer.AppendString(testCase), "invalid number should be rejected: %s", testCase)
+	}
+	validJSONCase := []string{`0`, `-0`, `0.5`, `-0.5e-5`, `[1E+2, 3e4]`, `{"a":-12.5}`}
+	for _, testCase := range validJSONCase {
+		lexer := NewLexer(WithStrict())
+		assert.Nil(t, lexer.AppendString(testCase), "valid number should be accepted: %s", testCase)
+	}
+}
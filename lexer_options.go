@@ -0,0 +1,124 @@
+package streamingjsongo
+
+// lexer option, used by NewLexer() for configuring the lexer
+type Option func(lexer *Lexer)
+
+// options of lexer
+type lexerOptions struct {
+	strict           bool             // strict mode, reject the byte which can never be part of a valid JSON
+	handler          Handler          // handler notified by events of JSON structures
+	omitDanglingKeys bool             // omit the object member of the key in lexing from the completed JSON and the partial value
+	atomicPolicy     CompletionPolicy // policy of hiding all partial strings and numbers, given by WithAtomicValues()
+	validUTF8        bool             // reject invalid UTF-8 sequences in strings
+	multiDocument    bool             // the stream holds multiple top-level values, like NDJSON
+	extractEmbedded  bool             // extract the JSON embedded in text and Markdown code fences
+	relaxed          bool             // accept JSON5 syntax and normalize it into strict JSON
+	pythonLiterals   bool             // rewrite Python literals into JSON values
+	nonFinite        nonFiniteValues  // JSON values of NaN, Infinity and -Infinity
+	repair           bool             // repair control characters and interior quotes in strings
+}
+
+// enable strict mode, the lexer will validate the JSON grammar incrementally
+// and return an error at the first byte that can never be part of a valid JSON
+func WithStrict() Option {
+	return func(lexer *Lexer) {
+		lexer.options.strict = true
+	}
+}
+
+// set the handler notified by events of JSON structures as they arrive, like object start, key and string fragment.
+// in lenient mode the handler is notified by OnError() at the first grammar error, and no events follow
+func WithHandler(handler Handler) Option {
+	return func(lexer *Lexer) {
+		lexer.options.handler = handler
+	}
+}
+
+// omit dangling keys, the object member of the key in lexing is removed with its preceding comma and padding,
+// like `{"a":1,"b` completes to `{"a":1}` instead of `{"a":1,"b":null}`, so only keys fully closed are completed
+func WithOmitDanglingKeys() Option {
+	return func(lexer *Lexer) {
+		lexer.options.omitDanglingKeys = true
+	}
+}
+
+// make all string and number values atomic, a partial string or number is hidden from the completed JSON and the partial value
+// until its closing quote or delimiter arrived, like `{"url":"https://exa` completes to `{"url":null}` by CompleteNull
+// or `{}` by CompleteOmit. other policies are ignored. see SetAtomic() for atomic values at given paths
+func WithAtomicValues(policy CompletionPolicy) Option {
+	return func(lexer *Lexer) {
+		if isAtomicPolicy(policy) {
+			lexer.options.atomicPolicy = policy
+		}
+	}
+}
+
+// reject invalid UTF-8 in strings, the lexer returns an error at the first byte which can never be part of a valid UTF-8 sequence,
+// like `\xff` or `\xe4` followed by `a`. without it, invalid sequences are kept as they are
+func WithValidUTF8() Option {
+	return func(lexer *Lexer) {
+		lexer.options.validUTF8 = true
+	}
+}
+
+// enable multiple documents mode, the JSON stream holds top-level values one after another, like `{"a":1}{"b":2}` or NDJSON.
+// each completed top-level value is kept as a document, returned by Documents() and notified by OnDocument(),
+// and the lexer continues with the next document, so CompleteJSON() and Value() complete only the trailing partial document.
+// a document broken by a grammar error is dropped and notified by OnDocumentError(), the rest of its line is skipped,
+// also in strict mode, so the documents after it in the same segment are lexed and no error is returned
+func WithMultipleDocuments() Option {
+	return func(lexer *Lexer) {
+		lexer.options.multiDocument = true
+	}
+}
+
+// extract the JSON embedded in text, like the model output "Here is the result:\n```json\n{...}\n```".
+// the text before the JSON is skipped, the JSON body starts after the opening code fence line or at the first `{` or `[`,
+// and it ends at the closing code fence or where the top-level value completed, the rest of the stream is skipped.
+// a `{` or `[` in text like `{tool}` is skipped if grammar rejects the next token, and the search goes on,
+// the handler already notified of the open container is notified by OnError().
+// in multiple documents mode, the text between JSON bodies is skipped as well
+func WithExtractEmbedded() Option {
+	return func(lexer *Lexer) {
+		lexer.options.extractEmbedded = true
+	}
+}
+
+// enable relaxed mode, the lexer accepts JSON5 syntax emitted by models and normalizes it into strict JSON on the fly:
+// `// line` and `/* block */` comments and trailing commas are dropped, `'single quoted'` strings and unquoted keys like
+// `{foo: 1}` are double quoted, and numbers like `0x1F`, `+1`, `.5` and `5.` are rewritten into `31`, `1`, `0.5` and `5.0`,
+// `NaN`, `Infinity` and `-Infinity` into `null` by default, see WithNonFiniteValues(). so CompleteJSON() always returns strict JSON.
+// a leading `0` or sign of a number is kept until the next byte, since it may start a hex number or `-Infinity`,
+// CompleteJSON() and Value() still complete it like `0x1` to `1`
+func WithRelaxed() Option {
+	return func(lexer *Lexer) {
+		lexer.options.relaxed = true
+	}
+}
+
+// rewrite Python literals in values into JSON values on the fly, `True`, `False` and `None` are rewritten into `true`,
+// `false` and `null`, and `NaN`, `Infinity` and `-Infinity` into `null` by default, see WithNonFiniteValues().
+// partial literals complete to the JSON values like partial JSON literals do, like `[Tr` completes to `[true]`, and `[-` to `[0]`
+func WithPythonLiterals() Option {
+	return func(lexer *Lexer) {
+		lexer.options.pythonLiterals = true
+	}
+}
+
+// set the JSON values `NaN`, `Infinity` and `-Infinity` are rewritten into by WithPythonLiterals() and WithRelaxed(), like `"NaN"`,
+// an empty value is `null`. the values must be valid JSON values, they are lexed as the stream
+func WithNonFiniteValues(nan string, infinity string, negativeInfinity string) Option {
+	return func(lexer *Lexer) {
+		lexer.options.nonFinite = nonFiniteValues{nan: nan, infinity: infinity, negativeInfinity: negativeInfinity}
+	}
+}
+
+// enable repair mode for common mistakes of models in strings: control characters like raw newlines and tabs are escaped,
+// a `\` before one is kept as an escaped backslash,
+// and a quote in a string closes it only if it is followed by `,`, `}`, `]` or `:`, otherwise it is an interior quote and escaped,
+// like `{"say":"a "b" c"}` is repaired into `{"say":"a \"b\" c"}`. the quote is kept until the next token arrives
+func WithRepair() Option {
+	return func(lexer *Lexer) {
+		lexer.options.repair = true
+	}
+}
@@ -0,0 +1,395 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parse JSON Pointer (RFC 6901) into reference tokens, the empty pointer refers to the whole document
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid json pointer `%s`, it must start with `/`", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("invalid json pointer `%s`, `~` must be escaped as `~0`", pointer)
+			}
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// get array index of JSON Pointer reference token, returns -1 if the token is not an array index
+func jsonPointerIndex(token string) int {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return -1
+	}
+	for i := 0; i < len(token); i++ {
+		if !isDigit(token[i]) {
+			return -1
+		}
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil {
+		return -1
+	}
+	return index
+}
+
+// parse JSON Pointer into reference tokens and their array indexes, the index is -1 if the token is not an array index
+func parseJSONPointerPath(pointer string) ([]string, []int, error) {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	indexes := make([]int, len(tokens))
+	for i, token := range tokens {
+		indexes[i] = jsonPointerIndex(token)
+	}
+	return tokens, indexes, nil
+}
+
+// subscription of the value at a JSON Pointer
+type pathSubscription struct {
+	tokens     []string              // reference tokens of JSON Pointer
+	indexes    []int                 // array indexes of reference tokens, -1 if the token is not an array index
+	onComplete func(json.RawMessage) // called when the value completed, can be nil
+	onUpdate   func(interface{})     // called when the value grew or completed, can be nil
+	completed  bool                  // the value completed in current segment, it is notified to onUpdate at the end of segment
+}
+
+// open container in path tracker
+type pathFrame struct {
+	isObject bool   // the container is an object, or an array
+	key      []byte // decoded key of the member in lexing
+	index    int    // index of the element in lexing, -1 before the first element
+	start    int    // offset of the container in JSON content
+}
+
+// completed value waiting for notification, the bytes of value are not written into JSON content until the byte is lexed
+type pathCompletion struct {
+	subscription *pathSubscription
+	start        int // offset of the value in JSON content
+	end          int // end offset of the value in JSON content
+}
+
+// path tracker tracks the path of the value in lexing, and notifies subscriptions at paths
+type pathTracker struct {
+	grammar       *grammar
+	subscriptions []*pathSubscription
+	frames        []pathFrame
+	inKey         bool // a key is in lexing
+	scalarOpen    bool // a string, number or literal is in lexing
+	scalarStart   int  // offset of the scalar in lexing
+	completions   []pathCompletion
+	differ        *Differ    // differ tracking the changed slots by the tracker, nil if none
+	dirtySlots    [][]string // reference tokens of the slots changed since the last Diff() of differ
+	dirtyAll      bool       // the whole value changed since the last Diff() of differ, like a new document started
+}
+
+// reset tracker for a new JSON stream, the subscriptions are kept
+func (tracker *pathTracker) reset() {
+	tracker.frames = tracker.frames[:0]
+	tracker.inKey = false
+	tracker.scalarOpen = false
+	tracker.completions = tracker.completions[:0]
+	for _, subscription := range tracker.subscriptions {
+		subscription.completed = false
+	}
+	tracker.dirtySlots = tracker.dirtySlots[:0]
+	tracker.dirtyAll = true
+}
+
+// check if the path of the slot in lexing at given depth matches the subscription to the depth
+func (tracker *pathTracker) matchPath(subscription *pathSubscription, depth int) bool {
+	for i := 0; i < depth; i++ {
+		frame := &tracker.frames[i]
+		if frame.isObject {
+			if string(frame.key) != subscription.tokens[i] {
+				return false
+			}
+		} else if frame.index != subscription.indexes[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// a value starts at the innermost slot, it moves to the next element in arrays
+func (tracker *pathTracker) startValue() {
+	framesLen := len(tracker.frames)
+	if framesLen > 0 && !tracker.frames[framesLen-1].isObject {
+		tracker.frames[framesLen-1].index++
+	}
+	tracker.markDirty(0)
+}
+
+// max count of changed slots recorded between two Diff() calls, the whole value is diffed when more slots changed
+const maxDirtySlots = 1024
+
+// record the innermost slot changed for the differ, next moves it to the next element in arrays
+func (tracker *pathTracker) markDirty(next int) {
+	if tracker.differ == nil || tracker.dirtyAll {
+		return
+	}
+	if len(tracker.dirtySlots) == maxDirtySlots {
+		tracker.dirtySlots = tracker.dirtySlots[:0]
+		tracker.dirtyAll = true
+		return
+	}
+	tokens := make([]string, len(tracker.frames))
+	for i := range tracker.frames {
+		frame := &tracker.frames[i]
+		if frame.isObject {
+			tokens[i] = string(frame.key)
+		} else if i == len(tracker.frames)-1 {
+			tokens[i] = strconv.Itoa(frame.index + next)
+		} else {
+			tokens[i] = strconv.Itoa(frame.index)
+		}
+	}
+	tracker.dirtySlots = append(tracker.dirtySlots, tokens)
+}
+
+// record the slot of the scalar or key in lexing, or the slot holding the value held by the stages before lexing,
+// they change without grammar events
+func (tracker *pathTracker) markOpenSlot(held bool) {
+	switch {
+	case tracker.scalarOpen:
+		tracker.markDirty(0)
+	case held:
+		tracker.markDirty(1)
+	}
+}
+
+// start tracking the slots changed for the next Diff(), the open slot keeps changing
+func (tracker *pathTracker) startDiff(held bool) {
+	tracker.dirtySlots = tracker.dirtySlots[:0]
+	tracker.dirtyAll = false
+	tracker.markOpenSlot(held)
+}
+
+// the value at the innermost slot completed
+func (tracker *pathTracker) completeValue(start int, end int) {
+	depth := len(tracker.frames)
+	for _, subscription := range tracker.subscriptions {
+		if len(subscription.tokens) != depth || !tracker.matchPath(subscription, depth) {
+			continue
+		}
+		if subscription.onComplete != nil {
+			tracker.completions = append(tracker.completions, pathCompletion{subscription: subscription, start: start, end: end})
+		}
+		subscription.completed = true
+	}
+}
+
+// check if the value of subscription is in lexing
+func (tracker *pathTracker) valueOpen(subscription *pathSubscription) bool {
+	depth := len(subscription.tokens)
+	if depth > len(tracker.frames) || !tracker.matchPath(subscription, depth) {
+		return false
+	}
+	// the value is a container holding the value in lexing, or it is the scalar in lexing
+	return depth < len(tracker.frames) || (tracker.scalarOpen && !tracker.inKey)
+}
+
+func (tracker *pathTracker) onObjectStart() {
+	tracker.startValue()
+	tracker.frames = append(tracker.frames, pathFrame{isObject: true, key: tracker.nextKeyBuffer(), index: -1, start: tracker.grammar.offset})
+}
+
+func (tracker *pathTracker) onObjectEnd() {
+	tracker.endContainer()
+}
+
+func (tracker *pathTracker) onArrayStart() {
+	tracker.startValue()
+	tracker.frames = append(tracker.frames, pathFrame{isObject: false, key: tracker.nextKeyBuffer(), index: -1, start: tracker.grammar.offset})
+}
+
+func (tracker *pathTracker) onArrayEnd() {
+	tracker.endContainer()
+}
+
+// reuse the key buffer of popped frame
+func (tracker *pathTracker) nextKeyBuffer() []byte {
+	framesLen := len(tracker.frames)
+	if framesLen < cap(tracker.frames) {
+		return tracker.frames[:framesLen+1][framesLen].key[:0]
+	}
+	return nil
+}
+
+// the innermost container ends at current byte
+func (tracker *pathTracker) endContainer() {
+	start := tracker.frames[len(tracker.frames)-1].start
+	tracker.frames = tracker.frames[:len(tracker.frames)-1]
+	tracker.completeValue(start, tracker.grammar.offset+1)
+}
+
+func (tracker *pathTracker) onStringStart(isKey bool) {
+	tracker.scalarOpen = true
+	tracker.inKey = isKey
+	if isKey {
+		frame := &tracker.frames[len(tracker.frames)-1]
+		frame.key = frame.key[:0]
+		// the partial key is stored with null value
+		tracker.markDirty(0)
+		return
+	}
+	tracker.startValue()
+	tracker.scalarStart = tracker.grammar.offset
+}
+
+func (tracker *pathTracker) onStringByte(c byte) {
+	if tracker.inKey {
+		frame := &tracker.frames[len(tracker.frames)-1]
+		frame.key = append(frame.key, c)
+	}
+}
+
+func (tracker *pathTracker) onStringEnd(isKey bool) {
+	tracker.scalarOpen = false
+	tracker.inKey = false
+	if !isKey {
+		tracker.completeValue(tracker.scalarStart, tracker.grammar.offset+1)
+		return
+	}
+	// the key awaits its value
+	tracker.markDirty(0)
+}
+
+func (tracker *pathTracker) onNumberStart() {
+	tracker.startValue()
+	tracker.scalarOpen = true
+	tracker.scalarStart = tracker.grammar.offset
+}
+
+func (tracker *pathTracker) onNumberByte(c byte) {}
+
+func (tracker *pathTracker) onNumberEnd() {
+	// the number is ended by the byte after it
+	tracker.scalarOpen = false
+	tracker.completeValue(tracker.scalarStart, tracker.grammar.offset)
+}
+
+func (tracker *pathTracker) onLiteralStart(literal string) {
+	tracker.startValue()
+	tracker.scalarOpen = true
+	tracker.scalarStart = tracker.grammar.offset
+}
+
+func (tracker *pathTracker) onLiteralEnd() {
+	tracker.scalarOpen = false
+	tracker.completeValue(tracker.scalarStart, tracker.grammar.offset+1)
+}
+
+// notify completed values to subscriptions, the bytes of them must be written into JSON content
+func (lexer *Lexer) dispatchCompletions() {
+	tracker := lexer.paths
+	for i := 0; i < len(tracker.completions); i++ {
+		completion := tracker.completions[i]
+		raw := make(json.RawMessage, completion.end-completion.start)
+		copy(raw, lexer.JSONContent.Bytes()[completion.start:completion.end])
+		completion.subscription.onComplete(raw)
+	}
+	tracker.completions = tracker.completions[:0]
+}
+
+// notify updated values to subscriptions at the end of segment
+func (lexer *Lexer) dispatchUpdates() {
+	tracker := lexer.paths
+	for _, subscription := range tracker.subscriptions {
+		if subscription.onUpdate == nil || (!subscription.completed && !tracker.valueOpen(subscription)) {
+			continue
+		}
+		subscription.completed = false
+		value, err := lexer.Value()
+		if err != nil {
+			return
+		}
+		if value, ok := lookupJSONPointer(value, subscription.tokens); ok {
+			subscription.onUpdate(value)
+		}
+	}
+}
+
+// get value at JSON Pointer reference tokens in the partial value
+func lookupJSONPointer(value interface{}, tokens []string) (interface{}, bool) {
+	for _, token := range tokens {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			element, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			value = element
+		case []interface{}:
+			index := jsonPointerIndex(token)
+			if index < 0 || index >= len(v) {
+				return nil, false
+			}
+			value = v[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// attach a path tracker to the grammar
+func (lexer *Lexer) attachPathTracker() error {
+	tracker := &pathTracker{grammar: &lexer.grammar}
+	if err := lexer.attachListener(tracker); err != nil {
+		return err
+	}
+	// values completed before attached are not notified
+	tracker.completions = tracker.completions[:0]
+	lexer.paths = tracker
+	return nil
+}
+
+// add subscription at JSON Pointer, the path tracker is attached by the first subscription
+func (lexer *Lexer) subscribe(pointer string, subscription *pathSubscription) error {
+	tokens, indexes, err := parseJSONPointerPath(pointer)
+	if err != nil {
+		return err
+	}
+	subscription.tokens = tokens
+	subscription.indexes = indexes
+	if lexer.paths == nil {
+		if err := lexer.attachPathTracker(); err != nil {
+			return err
+		}
+	}
+	if subscription.onUpdate != nil && lexer.value == nil {
+		if err := lexer.attachValueBuilder(); err != nil {
+			return err
+		}
+	}
+	lexer.paths.subscriptions = append(lexer.paths.subscriptions, subscription)
+	return nil
+}
+
+// subscribe to the completion of the value at given JSON Pointer (RFC 6901), like `/command` or `/args/0/text`,
+// callback is called with the raw JSON of the value as soon as the value completed.
+// values completed before subscribed are not notified, so subscribe before appending the JSON stream.
+// a top-level number is not complete until a byte after it arrived
+func (lexer *Lexer) OnComplete(pointer string, callback func(raw json.RawMessage)) error {
+	return lexer.subscribe(pointer, &pathSubscription{onComplete: callback})
+}
+
+// subscribe to the updates of the value at given JSON Pointer (RFC 6901), like `/args/0/text`,
+// callback is called at the end of each appended segment in which the value grew or completed,
+// with the partial value like Value() returns, the maps and slices in it are owned by the lexer
+func (lexer *Lexer) OnUpdate(pointer string, callback func(value interface{})) error {
+	return lexer.subscribe(pointer, &pathSubscription{onUpdate: callback})
+}
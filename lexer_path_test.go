@@ -0,0 +1,73 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSONPointer(t *testing.T) {
+	tokens, err := parseJSONPointer(``)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(tokens))
+
+	tokens, err = parseJSONPointer(`/a~1b/m~0n/0/`)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a/b", "m~n", "0", ""}, tokens)
+
+	_, err = parseJSONPointer(`a`)
+	assert.NotNil(t, err)
+	_, err = parseJSONPointer(`/a~2`)
+	assert.NotNil(t, err)
+}
+
+func TestOnComplete(t *testing.T) {
+	completed := map[string]string{}
+	subscribe := func(lexer *Lexer, pointer string) {
+		assert.Nil(t, lexer.OnComplete(pointer, func(raw json.RawMessage) {
+			completed[pointer] = string(raw)
+		}))
+	}
+	lexer := NewLexer()
+	for _, pointer := range []string{``, `/command`, `/args`, `/args/0/text`, `/args/1`, `/n`, `/a~1b`} {
+		subscribe(lexer, pointer)
+	}
+
+	assert.Nil(t, lexer.AppendString(`{"command": "sea`))
+	assert.Equal(t, map[string]string{}, completed)
+	assert.Nil(t, lexer.AppendString(`rch", "args": [{"text": "a\"b"}, `))
+	assert.Equal(t, map[string]string{`/command`: `"search"`, `/args/0/text`: `"a\"b"`}, completed)
+	assert.Nil(t, lexer.AppendString(`-1.5e3 , tr`))
+	assert.Equal(t, `-1.5e3`, completed[`/args/1`])
+	assert.Nil(t, lexer.AppendString(`ue], "n": 12`))
+	assert.Equal(t, `[{"text": "a\"b"}, -1.5e3 , true]`, completed[`/args`])
+	assert.Equal(t, "", completed[`/n`])
+	assert.Nil(t, lexer.AppendString(` , "a/b": {}}`))
+	assert.Equal(t, `12`, completed[`/n`])
+	assert.Equal(t, `{}`, completed[`/a~1b`])
+	assert.Equal(t, lexer.CompleteJSON(), completed[``])
+}
+
+func TestOnUpdate(t *testing.T) {
+	var updates []interface{}
+	lexer := NewLexer()
+	assert.Nil(t, lexer.AppendString(`{"args": [{"text": "He`))
+	assert.Nil(t, lexer.OnUpdate(`/args/0/text`, func(value interface{}) {
+		updates = append(updates, value)
+	}))
+	for _, segment := range []string{`llo`, ` world`, `"}, {"text": "x"}`, `]}`} {
+		assert.Nil(t, lexer.AppendString(segment))
+	}
+	assert.Equal(t, []interface{}{"Hello", "Hello world", "Hello world"}, updates)
+
+	// subscriptions are kept after reset
+	updates = nil
+	lexer.Reset()
+	assert.Nil(t, lexer.AppendString(`{"args": [{"text": [1`))
+	assert.Nil(t, lexer.AppendString(`, 2`))
+	assert.Equal(t, []interface{}{
+		[]interface{}{json.Number("1")},
+		[]interface{}{json.Number("1"), json.Number("2")},
+	}, updates)
+}
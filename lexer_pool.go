@@ -0,0 +1,33 @@
+package streamingjsongo
+
+import (
+	"sync"
+)
+
+// pool of lexers, lexers in pool are reset and have no options
+var lexerPool = sync.Pool{
+	New: func() interface{} {
+		return NewLexer()
+	},
+}
+
+// acquire a lexer from pool, the lexer should be released by ReleaseLexer() after the JSON stream finished
+func AcquireLexer(options ...Option) *Lexer {
+	lexer := lexerPool.Get().(*Lexer)
+	for _, option := range options {
+		option(lexer)
+	}
+	lexer.resetListeners()
+	return lexer
+}
+
+// release a lexer into pool, the lexer and the JSON content from it must not be used after released
+func ReleaseLexer(lexer *Lexer) {
+	lexer.options = lexerOptions{}
+	lexer.paths = nil
+	lexer.atomicPaths = nil
+	lexer.onDocument = nil
+	lexer.onDocumentError = nil
+	lexer.Reset()
+	lexerPool.Put(lexer)
+}
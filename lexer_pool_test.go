@@ -0,0 +1,48 @@
+package streamingjsongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReset(t *testing.T) {
+	lexer := NewLexer(WithStrict())
+	assert.Nil(t, lexer.AppendString(`{"a":[1, 2], "b": "c`))
+	assert.Equal(t, `{"a":[1, 2], "b": "c"}`, lexer.CompleteJSON())
+
+	lexer.Reset()
+	assert.Equal(t, ``, lexer.CompleteJSON())
+	assert.Equal(t, 0, len(lexer.TokenStack))
+	assert.Equal(t, 0, len(lexer.MirrorTokenStack))
+
+	// the lexer works as a new one, and keeps its options
+	assert.Nil(t, lexer.AppendString(`[tr`))
+	assert.Equal(t, `[true]`, lexer.CompleteJSON())
+	assert.NotNil(t, lexer.AppendString(`x`))
+}
+
+func TestAcquireLexer(t *testing.T) {
+	lexer := AcquireLexer(WithStrict())
+	assert.Nil(t, lexer.AppendString(`{"a":`))
+	assert.Equal(t, `{"a":null}`, lexer.CompleteJSON())
+	ReleaseLexer(lexer)
+
+	// options are not kept after released
+	lexer = AcquireLexer()
+	assert.Nil(t, lexer.AppendString(`[1 2`))
+	assert.Equal(t, `[1 2]`, lexer.CompleteJSON())
+	ReleaseLexer(lexer)
+}
+
+func TestReset_allocs(t *testing.T) {
+	streamingJSONContent := `{"string": "这是一个字符串", "integer": 42, "float": 3.14159, "array":["string in array", 123, 45.67, true, false, null, {"object_in_array": "object_value"},["nested_array"]]}`
+	lexer := NewLexer()
+	allocs := testing.AllocsPerRun(100, func() {
+		lexer.Reset()
+		if err := lexer.AppendString(streamingJSONContent); err != nil {
+			panic(err)
+		}
+	})
+	assert.Equal(t, float64(0), allocs)
+}
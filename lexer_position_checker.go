@@ -108,29 +108,6 @@ func (lexer *Lexer) streamStoppedInAnObjectObjectValueStart() bool {
 	return matchStack(lexer.TokenStack, case1) && matchStack(lexer.MirrorTokenStack, case2)
 }
 
-// check if JSON stream stopped in an object properity's negative number value, like `:-`
-func (lexer *Lexer) streamStoppedInAnObjectNegativeNumberValueStart() bool {
-	// `:`, `-` in stack
-	case1 := []int{
-		TOKEN_COLON,
-		TOKEN_NEGATIVE,
-	}
-	return matchStack(lexer.TokenStack, case1)
-}
-
-// check if JSON stream stopped in an object properity's negative number value, like `-`
-func (lexer *Lexer) streamStoppedInANegativeNumberValueStart() bool {
-	// `-` in stack
-	case1 := []int{
-		TOKEN_NEGATIVE,
-	}
-	// `0`in mirror stack
-	case2 := []int{
-		TOKEN_NUMBER_0,
-	}
-	return matchStack(lexer.TokenStack, case1) && matchStack(lexer.MirrorTokenStack, case2)
-}
-
 // check if JSON stream stopped in an array
 func (lexer *Lexer) streamStoppedInAnArray() bool {
 	return lexer.getTopTokenOnMirrorStack() == TOKEN_RIGHT_BRACKET
@@ -188,22 +165,29 @@ func (lexer *Lexer) streamStoppedInANumber() bool {
 	return lexer.getTopTokenOnStack() == TOKEN_NUMBER
 }
 
-// check if JSON stream stopped in a number first decimal place, like `.?`
-func (lexer *Lexer) streamStoppedInANumberDecimalPart() bool {
-	return lexer.getTopTokenOnStack() == TOKEN_DOT
+// check if JSON stream stopped in escape character, like `\`
+func (lexer *Lexer) streamStoppedWithLeadingEscapeCharacter() bool {
+	return lexer.getTopTokenOnStack() == TOKEN_ESCAPE_CHARACTER
+}
+
+// check if JSON stream stopped in a top-level string value start, like `"`
+func (lexer *Lexer) streamStoppedInATopLevelStringValueStart() bool {
+	// only `"` in stack, and nothing in mirror stack
+	return len(lexer.TokenStack) == 1 && lexer.getTopTokenOnStack() == TOKEN_QUOTE && len(lexer.MirrorTokenStack) == 0
 }
 
-// check if JSON stream stopped in a number other decimal place (except first place), like `.[0-9]?`
-func (lexer *Lexer) streamStoppedInANumberDecimalPartMiddle() bool {
-	// `.`, TOKEN_NUMBER in stack
+// check if JSON stream stopped in a top-level string value end, like `"value"`
+func (lexer *Lexer) streamStoppedInATopLevelStringValueEnd() bool {
+	// only `"`, `"` in stack
 	case1 := []int{
-		TOKEN_DOT,
-		TOKEN_NUMBER,
+		TOKEN_QUOTE,
+		TOKEN_QUOTE,
 	}
-	return matchStack(lexer.TokenStack, case1)
+	// only `"` in mirror stack
+	return len(lexer.TokenStack) == 2 && matchStack(lexer.TokenStack, case1) && len(lexer.MirrorTokenStack) == 1 && lexer.getTopTokenOnMirrorStack() == TOKEN_QUOTE
 }
 
-// check if JSON stream stopped in escape character, like `\`
-func (lexer *Lexer) streamStoppedWithLeadingEscapeCharacter() bool {
-	return lexer.getTopTokenOnStack() == TOKEN_ESCAPE_CHARACTER
+// check if JSON stream stopped in a string or in an escape of it, like `"a\`
+func (lexer *Lexer) streamStoppedInAStringOrEscape() bool {
+	return lexer.getTopTokenOnMirrorStack() == TOKEN_QUOTE
 }
@@ -0,0 +1,152 @@
+package streamingjsongo
+
+// Python literals
+const (
+	pythonTrue             = "True"
+	pythonFalse            = "False"
+	pythonNone             = "None"
+	pythonNaN              = "NaN"
+	pythonInfinity         = "Infinity"
+	pythonNegativeInfinity = "-Infinity"
+)
+
+// JSON values of non-finite numbers, an empty value is `null`
+type nonFiniteValues struct {
+	nan              string
+	infinity         string
+	negativeInfinity string
+}
+
+// Python literal rewriter rewrites Python literals in values into JSON values, like `True` into `true`.
+// the JSON value is lexed when the literal completes, partial literals complete to it like partial JSON literals do
+type pythonRewriter struct {
+	held  byte      // `N` or `-` kept until the next byte determines the literal, 0 if none
+	rest  string    // rest bytes of the Python literal in lexing, like `rue` of `True`
+	value heldValue // JSON value of the Python literal in lexing
+}
+
+// reset rewriter for a new JSON stream
+func (rewriter *pythonRewriter) reset() {
+	rewriter.held = 0
+	rewriter.rest = ""
+	rewriter.value = heldValue{}
+}
+
+// get the byte or literal kept until the following bytes determine it, it completes like the non-Python one does
+func (rewriter *pythonRewriter) heldValue() (heldValue, bool) {
+	switch {
+	case rewriter.rest != "":
+		return rewriter.value, true
+	case rewriter.held == 'N':
+		return heldValue{value: tokenSymbolMap[TOKEN_NULL], zero: tokenSymbolMap[TOKEN_NULL]}, true
+	case rewriter.held == TOKEN_NEGATIVE_SYMBOL:
+		// a partial number like `-`
+		return heldValue{value: "0", zero: "0", number: true}, true
+	}
+	return heldValue{}, false
+}
+
+// get the held literal of a JSON literal, it completes to the zero value of its type
+func jsonLiteral(token int, zeroToken int) heldValue {
+	return heldValue{value: tokenSymbolMap[token], zero: tokenSymbolMap[zeroToken]}
+}
+
+// get the held literal of a non-finite number, its type depends on the value
+func nonFiniteLiteral(value string) heldValue {
+	return heldValue{value: nonFiniteValue(value), zero: tokenSymbolMap[TOKEN_NULL]}
+}
+
+// get the JSON value of a non-finite number
+func nonFiniteValue(value string) string {
+	if value == "" {
+		return tokenSymbolMap[TOKEN_NULL]
+	}
+	return value
+}
+
+// check if the grammar expects a value, Python literals are only rewritten in values
+func (lexer *Lexer) streamExpectsValue() bool {
+	return lexer.grammar.state == grammarStateValue || lexer.grammar.state == grammarStateArrayValueOrEnd
+}
+
+// lex the rewritten bytes, the strings in them are repaired first
+func (lexer *Lexer) lexRewritten(position streamPosition, rewritten ...byte) *SyntaxError {
+	if !lexer.options.repair {
+		return lexer.lexRepaired(position, rewritten...)
+	}
+	for _, c := range rewritten {
+		if syntaxError := lexer.repairByte(c, position); syntaxError != nil {
+			return syntaxError
+		}
+	}
+	return nil
+}
+
+// keep the JSON value of a Python literal, it is lexed when the rest bytes of the literal arrive
+func (lexer *Lexer) rewriteLiteral(value heldValue, literal string, matched int) *SyntaxError {
+	lexer.python.rest = literal[matched:]
+	lexer.python.value = value
+	return nil
+}
+
+// lex the JSON value of the Python literal in lexing
+func (lexer *Lexer) lexRewrittenLiteral(position streamPosition) *SyntaxError {
+	value := lexer.python.value.value
+	for i := 0; i < len(value); i++ {
+		if syntaxError := lexer.lexRewritten(position, value[i]); syntaxError != nil {
+			return syntaxError
+		}
+	}
+	return nil
+}
+
+// rewrite a byte of Python literals and lex it
+func (lexer *Lexer) rewriteByte(c byte, position streamPosition) *SyntaxError {
+	rewriter := &lexer.python
+	if rewriter.rest != "" {
+		if c == rewriter.rest[0] {
+			rewriter.rest = rewriter.rest[1:]
+			if rewriter.rest != "" {
+				return nil
+			}
+			return lexer.lexRewrittenLiteral(position)
+		}
+		// the literal is broken in an invalid stream, its value is lexed before the byte
+		rewriter.rest = ""
+		if syntaxError := lexer.lexRewrittenLiteral(position); syntaxError != nil {
+			return syntaxError
+		}
+	}
+	if rewriter.held != 0 {
+		held := rewriter.held
+		rewriter.held = 0
+		switch {
+		case held == 'N' && c == 'o':
+			return lexer.rewriteLiteral(jsonLiteral(TOKEN_NULL, TOKEN_NULL), pythonNone, 2)
+		case held == 'N' && c == 'a':
+			return lexer.rewriteLiteral(nonFiniteLiteral(lexer.options.nonFinite.nan), pythonNaN, 2)
+		case held == TOKEN_NEGATIVE_SYMBOL && c == 'I':
+			return lexer.rewriteLiteral(nonFiniteLiteral(lexer.options.nonFinite.negativeInfinity), pythonNegativeInfinity, 2)
+		}
+		// not a Python literal, like `-1`
+		if syntaxError := lexer.lexRewritten(position, held); syntaxError != nil {
+			return syntaxError
+		}
+	}
+	if lexer.streamStoppedInAStringOrEscape() || !lexer.streamExpectsValue() {
+		return lexer.lexRewritten(position, c)
+	}
+	switch c {
+	case 'T':
+		return lexer.rewriteLiteral(jsonLiteral(TOKEN_TRUE, TOKEN_FLASE), pythonTrue, 1)
+	case 'F':
+		return lexer.rewriteLiteral(jsonLiteral(TOKEN_FLASE, TOKEN_FLASE), pythonFalse, 1)
+	case 'I':
+		return lexer.rewriteLiteral(nonFiniteLiteral(lexer.options.nonFinite.infinity), pythonInfinity, 1)
+	case 'N', TOKEN_NEGATIVE_SYMBOL:
+		// `None` or `NaN`, `-Infinity` or a number
+		rewriter.held = c
+		return nil
+	}
+	return lexer.lexRewritten(position, c)
+}
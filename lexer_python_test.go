@@ -0,0 +1,90 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPythonLiterals(t *testing.T) {
+	streamingJSONCase := map[string]string{
+		`[True, False, None]`:            `[true, false, null]`,
+		`[NaN, Infinity, -Infinity, -1]`: `[null, null, null, -1]`,
+		`[Tr`:                            `[true]`,
+		`{"a": Fa`:                       `{"a": false}`,
+		`[N`:                             `[null]`,
+		`[No`:                            `[null]`,
+		`[Na`:                            `[null]`,
+		`[Inf`:                           `[null]`,
+		`[-`:                             `[0]`,
+		`[-I`:                            `[null]`,
+		`[-1`:                            `[-1]`,
+		`{"a": -`:                        `{"a": 0}`,
+		`{"True": "None"}`:               `{"True": "None"}`,
+		`None`:                           `null`,
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer(WithPythonLiterals())
+		assert.Nil(t, lexer.AppendString(testCase))
+		assert.Equal(t, expect, lexer.CompleteJSON(), "unexpected completion in case: %s", testCase)
+	}
+}
+
+func TestWithPythonLiterals_held(t *testing.T) {
+	// bytes kept by the rewriter complete like the same JSON bytes do without it
+	streamingJSONCase := map[string]string{
+		`-`:             `-`,
+		`{"a": -`:       `{"a": -`,
+		`[1, -`:         `[1, -`,
+		`{"a": Tr`:      `{"a": tr`,
+		`{"a": [Fals`:   `{"a": [fals`,
+		`{"a":1,"b":-`:  `{"a":1,"b":-`,
+		`{"a":1,"b":No`: `{"a":1,"b":nu`,
+	}
+	for pythonCase, jsonCase := range streamingJSONCase {
+		for _, options := range [][]Option{nil, {WithAtomicValues(CompleteOmit)}} {
+			pythonLexer := NewLexer(append(options, WithPythonLiterals())...)
+			jsonLexer := NewLexer(options...)
+			assert.Nil(t, pythonLexer.AppendString(pythonCase))
+			assert.Nil(t, jsonLexer.AppendString(jsonCase))
+			assert.Equal(t, jsonLexer.CompleteJSON(), pythonLexer.CompleteJSON(), "unexpected completion in case: %s", pythonCase)
+			pythonValue, err := pythonLexer.Value()
+			assert.Nil(t, err)
+			jsonValue, err := jsonLexer.Value()
+			assert.Nil(t, err)
+			assert.Equal(t, jsonValue, pythonValue, "unexpected value in case: %s", pythonCase)
+		}
+	}
+}
+
+func TestWithNonFiniteValues(t *testing.T) {
+	lexer := NewLexer(WithPythonLiterals(), WithNonFiniteValues(`"NaN"`, `"Infinity"`, `"-Infinity"`))
+	assert.Nil(t, lexer.AppendString(`[NaN, Infinity, -Infinity, None]`))
+	assert.Equal(t, `["NaN", "Infinity", "-Infinity", null]`, lexer.CompleteJSON())
+}
+
+func TestWithPythonLiterals_streaming(t *testing.T) {
+	stream := "{a: True, 'b': [None, -Infinity, -2.5], // note\n c: False,}"
+	lexer := NewLexer(WithPythonLiterals(), WithRelaxed(), WithStrict())
+	for i := 0; i < len(stream); i++ {
+		assert.Nil(t, lexer.AppendString(stream[i:i+1]))
+		completedJSON := lexer.CompleteJSON()
+		assert.True(t, json.Valid([]byte(completedJSON)), "invalid completion %s of: %s", completedJSON, stream[:i+1])
+	}
+	value, err := lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"a": true,
+		"b": []interface{}{nil, nil, json.Number("-2.5")},
+		"c": false,
+	}, value)
+}
+
+func TestWithPythonLiterals_strict(t *testing.T) {
+	invalidJSONCase := []string{`[Trux]`, `[Nx]`, `[-x]`, `{None: 1}`}
+	for _, testCase := range invalidJSONCase {
+		lexer := NewLexer(WithPythonLiterals(), WithStrict())
+		assert.NotNil(t, lexer.AppendString(testCase), "expected error in case: %s", testCase)
+	}
+}
@@ -0,0 +1,322 @@
+package streamingjsongo
+
+import (
+	"math/big"
+)
+
+// relaxed JSON normalizer state const
+const (
+	relaxStateNone               = iota // not in a JSON5 structure
+	relaxStateSlash                     // after `/`, it starts a comment
+	relaxStateLineComment               // in a line comment, like `// comment`
+	relaxStateBlockComment              // in a block comment, like `/* comment`
+	relaxStateBlockCommentStar          // after `*` in a block comment, like `/* comment *`
+	relaxStateSingleQuoted              // in a single quoted string, like `'abc`
+	relaxStateSingleQuotedEscape        // after escape character in a single quoted string, like `'abc\`
+	relaxStateUnquotedKey               // in an unquoted key, like `{foo`
+	relaxStateSign                      // after the sign of a number, like `+` or `-`
+	relaxStateZero                      // after the leading `0` of a number, it may start a hex number
+	relaxStateHex                       // in a hex number, like `0x1F`
+	relaxStateLiteral                   // in a literal, like `Infin`
+)
+
+// relaxed JSON normalizer normalizes JSON5 syntax into strict JSON byte by byte:
+// comments are dropped, trailing commas are dropped, single quoted strings and unquoted keys are double quoted,
+// and numbers like `0x1F`, `+1`, `.5`, `5.`, `Infinity` and `NaN` are rewritten into JSON values
+type jsonNormalizer struct {
+	state   int    // current normalizer state
+	pending []byte // comma and whitespace after it, kept until the next token tells if the comma is trailing
+	sign    byte   // sign of the number in lexing kept until the next byte, 0 if none
+	digits  []byte // digits of the hex number in lexing
+	rest    string // rest bytes of the literal in lexing, like `ity` of `Infinity`
+	value   string // JSON value of the literal in lexing, lexed when the literal completes
+}
+
+// reset normalizer for a new JSON stream
+func (normalizer *jsonNormalizer) reset() {
+	normalizer.state = relaxStateNone
+	normalizer.pending = normalizer.pending[:0]
+	normalizer.sign = 0
+	normalizer.digits = normalizer.digits[:0]
+	normalizer.rest = ""
+	normalizer.value = ""
+}
+
+// get the number with its sign, the sign kept is taken, a leading `+` is dropped
+func (normalizer *jsonNormalizer) signed(number string) []byte {
+	sign := normalizer.sign
+	normalizer.sign = 0
+	if sign == TOKEN_NEGATIVE_SYMBOL {
+		return append([]byte{sign}, number...)
+	}
+	return []byte(number)
+}
+
+// get the number or literal kept until the following bytes determine it, it completes like the lexed one does
+func (normalizer *jsonNormalizer) heldValue() (heldValue, bool) {
+	number := ""
+	switch normalizer.state {
+	case relaxStateSign:
+		return heldValue{value: "0", zero: "0", number: true}, true
+	case relaxStateZero:
+		number = "0"
+	case relaxStateHex:
+		number = "0"
+		if len(normalizer.digits) > 0 {
+			number = decimalOfHex(normalizer.digits)
+		}
+	case relaxStateLiteral:
+		return heldValue{value: normalizer.value, zero: tokenSymbolMap[TOKEN_NULL]}, true
+	default:
+		return heldValue{}, false
+	}
+	if normalizer.sign == TOKEN_NEGATIVE_SYMBOL {
+		number = "-" + number
+	}
+	return heldValue{value: number, zero: "0", number: true}, true
+}
+
+// get the decimal number of the hex digits, like `1F` to `31`, `0x` without digits is kept for grammar to reject it
+func decimalOfHex(digits []byte) string {
+	if len(digits) == 0 {
+		return "0x"
+	}
+	var value big.Int
+	value.SetString(string(digits), 16)
+	return value.String()
+}
+
+// check if byte can be part of an unquoted key, like `_foo$1`, bytes of multi-byte UTF-8 characters are accepted
+func isUnquotedKeyByte(c byte) bool {
+	return isDigit(c) || isUnquotedKeyStart(c)
+}
+
+// check if byte can start an unquoted key
+func isUnquotedKeyStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || c == '$' || c >= 0x80
+}
+
+// lex the normalized bytes, Python literals in them are rewritten first
+func (lexer *Lexer) lexNormalized(position streamPosition, normalized ...byte) *SyntaxError {
+	if !lexer.options.pythonLiterals {
+		return lexer.lexRewritten(position, normalized...)
+	}
+	for _, c := range normalized {
+		if syntaxError := lexer.rewriteByte(c, position); syntaxError != nil {
+			return syntaxError
+		}
+	}
+	return nil
+}
+
+// keep the JSON value of a JSON5 literal, it is lexed when the rest bytes of the literal arrive
+func (lexer *Lexer) normalizeLiteral(value string, literal string) *SyntaxError {
+	lexer.relaxed.state = relaxStateLiteral
+	lexer.relaxed.rest = literal[1:]
+	lexer.relaxed.value = value
+	return nil
+}
+
+// lex the comma and whitespace kept, the comma is dropped if it is trailing
+func (lexer *Lexer) flushPendingComma(position streamPosition, trailing bool) *SyntaxError {
+	pending := lexer.relaxed.pending
+	if len(pending) == 0 {
+		return nil
+	}
+	lexer.relaxed.pending = pending[:0]
+	if trailing {
+		pending = pending[1:]
+	}
+	return lexer.lexNormalized(position, pending...)
+}
+
+// normalize a byte of relaxed JSON and lex the normalized bytes
+func (lexer *Lexer) normalizeByte(c byte, position streamPosition) *SyntaxError {
+	normalizer := &lexer.relaxed
+	switch normalizer.state {
+	case relaxStateSlash:
+		switch c {
+		case TOKEN_SLASH_SYMBOL:
+			normalizer.state = relaxStateLineComment
+			return nil
+		case '*':
+			normalizer.state = relaxStateBlockComment
+			return nil
+		}
+		// not a comment in an invalid stream, keep the `/`
+		normalizer.state = relaxStateNone
+		if syntaxError := lexer.flushPendingComma(position, false); syntaxError != nil {
+			return syntaxError
+		}
+		if syntaxError := lexer.lexNormalized(position, TOKEN_SLASH_SYMBOL); syntaxError != nil {
+			return syntaxError
+		}
+	case relaxStateLineComment:
+		if c != '\n' {
+			return nil
+		}
+		// the newline ends the comment, it is lexed as whitespace
+		normalizer.state = relaxStateNone
+	case relaxStateBlockComment:
+		if c == '*' {
+			normalizer.state = relaxStateBlockCommentStar
+		}
+		return nil
+	case relaxStateBlockCommentStar:
+		switch c {
+		case TOKEN_SLASH_SYMBOL:
+			normalizer.state = relaxStateNone
+		case '*':
+		default:
+			normalizer.state = relaxStateBlockComment
+		}
+		return nil
+	case relaxStateSingleQuoted:
+		switch c {
+		case '\'':
+			normalizer.state = relaxStateNone
+			return lexer.lexNormalized(position, TOKEN_QUOTE_SYMBOL)
+		case TOKEN_QUOTE_SYMBOL:
+			return lexer.lexNormalized(position, TOKEN_ESCAPE_CHARACTER_SYMBOL, TOKEN_QUOTE_SYMBOL)
+		case TOKEN_ESCAPE_CHARACTER_SYMBOL:
+			// keep the escape character until the escaped byte arrives, `\'` is unescaped
+			normalizer.state = relaxStateSingleQuotedEscape
+			return nil
+		}
+		return lexer.lexNormalized(position, c)
+	case relaxStateSingleQuotedEscape:
+		normalizer.state = relaxStateSingleQuoted
+		if c == '\'' {
+			return lexer.lexNormalized(position, c)
+		}
+		return lexer.lexNormalized(position, TOKEN_ESCAPE_CHARACTER_SYMBOL, c)
+	case relaxStateUnquotedKey:
+		if isUnquotedKeyByte(c) {
+			return lexer.lexNormalized(position, c)
+		}
+		// the key ends before the byte
+		normalizer.state = relaxStateNone
+		if syntaxError := lexer.lexNormalized(position, TOKEN_QUOTE_SYMBOL); syntaxError != nil {
+			return syntaxError
+		}
+	case relaxStateSign:
+		normalizer.state = relaxStateNone
+		switch {
+		case c == '0':
+			normalizer.state = relaxStateZero
+			return nil
+		case c == '.':
+			return lexer.lexNormalized(position, normalizer.signed("0.")...)
+		case c == 'I' && !lexer.options.pythonLiterals:
+			value := lexer.options.nonFinite.infinity
+			if normalizer.sign == TOKEN_NEGATIVE_SYMBOL {
+				value = lexer.options.nonFinite.negativeInfinity
+			}
+			normalizer.sign = 0
+			return lexer.normalizeLiteral(nonFiniteValue(value), pythonInfinity)
+		}
+		if syntaxError := lexer.lexNormalized(position, normalizer.signed("")...); syntaxError != nil {
+			return syntaxError
+		}
+	case relaxStateZero:
+		if c == 'x' || c == 'X' {
+			normalizer.state = relaxStateHex
+			normalizer.digits = normalizer.digits[:0]
+			return nil
+		}
+		normalizer.state = relaxStateNone
+		if syntaxError := lexer.lexNormalized(position, normalizer.signed("0")...); syntaxError != nil {
+			return syntaxError
+		}
+	case relaxStateHex:
+		if isHexDigit(c) {
+			normalizer.digits = append(normalizer.digits, c)
+			return nil
+		}
+		// the hex number ends before the byte
+		normalizer.state = relaxStateNone
+		if syntaxError := lexer.lexNormalized(position, normalizer.signed(decimalOfHex(normalizer.digits))...); syntaxError != nil {
+			return syntaxError
+		}
+	case relaxStateLiteral:
+		if c == normalizer.rest[0] {
+			normalizer.rest = normalizer.rest[1:]
+			if normalizer.rest != "" {
+				return nil
+			}
+			normalizer.state = relaxStateNone
+			return lexer.lexNormalized(position, []byte(normalizer.value)...)
+		}
+		// the literal is broken in an invalid stream, its value is lexed before the byte
+		normalizer.state = relaxStateNone
+		normalizer.rest = ""
+		if syntaxError := lexer.lexNormalized(position, []byte(normalizer.value)...); syntaxError != nil {
+			return syntaxError
+		}
+	}
+
+	// strings are not normalized
+	if lexer.streamStoppedInAStringOrEscape() {
+		return lexer.lexNormalized(position, c)
+	}
+
+	// a decimal point without fraction digits like `5.` is followed by `0`
+	if lexer.grammar.inNumber() && !isDigit(c) {
+		content := lexer.JSONContent.Bytes()
+		if content[len(content)-1] == '.' {
+			if syntaxError := lexer.lexNormalized(position, '0'); syntaxError != nil {
+				return syntaxError
+			}
+		}
+	}
+
+	switch {
+	case isIgnoreToken(c) && len(normalizer.pending) > 0:
+		normalizer.pending = append(normalizer.pending, c)
+		return nil
+	case c == TOKEN_SLASH_SYMBOL:
+		normalizer.state = relaxStateSlash
+		return nil
+	case c == TOKEN_COMMA_SYMBOL:
+		if syntaxError := lexer.flushPendingComma(position, false); syntaxError != nil {
+			return syntaxError
+		}
+		normalizer.pending = append(normalizer.pending, c)
+		return nil
+	case c == TOKEN_RIGHT_BRACE_SYMBOL || c == TOKEN_RIGHT_BRACKET_SYMBOL:
+		if syntaxError := lexer.flushPendingComma(position, true); syntaxError != nil {
+			return syntaxError
+		}
+		return lexer.lexNormalized(position, c)
+	}
+
+	if syntaxError := lexer.flushPendingComma(position, false); syntaxError != nil {
+		return syntaxError
+	}
+	switch {
+	case c == '\'':
+		normalizer.state = relaxStateSingleQuoted
+		return lexer.lexNormalized(position, TOKEN_QUOTE_SYMBOL)
+	case isUnquotedKeyStart(c) && (lexer.grammar.state == grammarStateObjectKeyOrEnd || lexer.grammar.state == grammarStateObjectKey):
+		normalizer.state = relaxStateUnquotedKey
+		return lexer.lexNormalized(position, TOKEN_QUOTE_SYMBOL, c)
+	case !lexer.streamExpectsValue():
+	case c == '+' || c == TOKEN_NEGATIVE_SYMBOL:
+		// `-Infinity`, `-0x1F` or `-.5`
+		normalizer.state = relaxStateSign
+		normalizer.sign = c
+		return nil
+	case c == '0':
+		normalizer.state = relaxStateZero
+		return nil
+	case c == '.':
+		return lexer.lexNormalized(position, '0', '.')
+	case lexer.options.pythonLiterals:
+		// `Infinity` and `NaN` are rewritten by the Python literal rewriter
+	case c == 'I':
+		return lexer.normalizeLiteral(nonFiniteValue(lexer.options.nonFinite.infinity), pythonInfinity)
+	case c == 'N':
+		return lexer.normalizeLiteral(nonFiniteValue(lexer.options.nonFinite.nan), pythonNaN)
+	}
+	return lexer.lexNormalized(position, c)
+}
@@ -0,0 +1,154 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRelaxed(t *testing.T) {
+	streamingJSONCase := map[string]string{
+		`{foo: 1, bar: 'x', }`:              `{"foo": 1, "bar": "x" }`,
+		`{_a$1: [1, 2,], "b": {c: null,},}`: `{"_a$1": [1, 2], "b": {"c": null}}`,
+		"[1, /* two */ 2, // three\n]":      "[1,  2 \n]",
+		`'it\'s "ok" \n'`:                   `"it's \"ok\" \n"`,
+		`{"url": "http://x/*y*/"}`:          `{"url": "http://x/*y*/"}`,
+		`{foo`:                              `{"foo":null}`,
+		`{foo:`:                             `{"foo":null}`,
+		`{a: 'hel`:                          `{"a": "hel"}`,
+		`{a: 'hel\`:                         `{"a": "hel"}`,
+		`[1,`:                               `[1]`,
+		`[1, `:                              `[1]`,
+		`{a: 1 /`:                           `{"a": 1}`,
+		`{a: 1 /* comment`:                  `{"a": 1}`,
+		`// comment`:                        ``,
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer(WithRelaxed())
+		assert.Nil(t, lexer.AppendString(testCase))
+		assert.Equal(t, expect, lexer.CompleteJSON(), "unexpected completion in case: %s", testCase)
+	}
+}
+
+func TestWithRelaxed_streaming(t *testing.T) {
+	stream := "// tool call\n{name: 'get_weather', /* args */ arguments: {city: 'Paris', days: [1, 2,],}, 'quote': 'a\"b',}"
+	lexer := NewLexer(WithRelaxed(), WithStrict())
+	for i := 0; i < len(stream); i++ {
+		assert.Nil(t, lexer.AppendString(stream[i:i+1]))
+		completedJSON := lexer.CompleteJSON()
+		if completedJSON != `` {
+			assert.True(t, json.Valid([]byte(completedJSON)), "invalid completion %s of: %s", completedJSON, stream[:i+1])
+		}
+	}
+	value, err := lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name":      "get_weather",
+		"arguments": map[string]interface{}{"city": "Paris", "days": []interface{}{json.Number("1"), json.Number("2")}},
+		"quote":     `a"b`,
+	}, value)
+}
+
+func TestWithRelaxed_strict(t *testing.T) {
+	invalidJSONCase := []string{`{foo 1}`, `[1,,2]`, `{a: 1 /x}`, `[a]`, `[0x]`, `[0xG]`, `[Infinite]`}
+	for _, testCase := range invalidJSONCase {
+		lexer := NewLexer(WithRelaxed(), WithStrict())
+		assert.NotNil(t, lexer.AppendString(testCase), "expected error in case: %s", testCase)
+	}
+}
+
+func TestWithRelaxed_numbers(t *testing.T) {
+	streamingJSONCase := map[string]string{
+		`{a: 0x1F, b: +1}`:                          `{"a": 31, "b": 1}`,
+		`[-0x1f, 0XFFFFFFFFFFFFFFFFFF, 0, -0, 0.5]`: `[-31, 4722366482869645213695, 0, -0, 0.5]`,
+		`[.5, -.5, +.5, 5., -5.e3, 5./* c */]`:      `[0.5, -0.5, 0.5, 5.0, -5.0e3, 5.0]`,
+		`[Infinity, -Infinity, +Infinity, NaN]`:     `[null, null, null, null]`,
+		`{a: Infin`:                                 `{"a": null}`,
+		`[0x1F`:                                     `[31]`,
+		`[5.`:                                       `[5.0]`,
+		`{a: -`:                                     `{"a": 0}`,
+		`["+1", 'x.5', {'0x1F': 1}]`:                `["+1", "x.5", {"0x1F": 1}]`,
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer(WithRelaxed())
+		assert.Nil(t, lexer.AppendString(testCase))
+		assert.Equal(t, expect, lexer.CompleteJSON(), "unexpected completion in case: %s", testCase)
+	}
+
+	// non-finite numbers are rewritten into the values given by WithNonFiniteValues()
+	lexer := NewLexer(WithRelaxed(), WithNonFiniteValues(`"NaN"`, `1e999`, `-1e999`))
+	assert.Nil(t, lexer.AppendString(`[NaN, Infinity, -Infinity]`))
+	assert.Equal(t, `["NaN", 1e999, -1e999]`, lexer.CompleteJSON())
+
+	// the Python literals are rewritten with the numbers
+	lexer = NewLexer(WithRelaxed(), WithPythonLiterals())
+	assert.Nil(t, lexer.AppendString(`{a: None, b: -Infinity, c: +Infinity, d: NaN, e: 0x10}`))
+	assert.Equal(t, `{"a": null, "b": null, "c": null, "d": null, "e": 16}`, lexer.CompleteJSON())
+}
+
+func TestWithRelaxed_heldNumbers(t *testing.T) {
+	// numbers kept by the normalizer until the next byte complete like the lexed ones do
+	streamingJSONCase := map[string]string{
+		`0`:        `0`,
+		`-`:        `0`,
+		` -0`:      ` -0`,
+		`[1, -`:    `[1, 0]`,
+		`[1, 0x`:   `[1, 0]`,
+		`[-0x1F`:   `[-31]`,
+		`{a: 0`:    `{"a": 0}`,
+		`{a: -Inf`: `{"a": null}`,
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer(WithRelaxed())
+		assert.Nil(t, lexer.AppendString(testCase))
+		assert.Equal(t, expect, lexer.CompleteJSON(), "unexpected completion in case: %s", testCase)
+	}
+
+	// the value holds the number too, it is replaced when the number is lexed
+	lexer := NewLexer(WithRelaxed())
+	assert.Nil(t, lexer.AppendString(`{a: [1, 0x1`))
+	value, err := lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{json.Number("1"), json.Number("1")}}, value)
+	assert.Nil(t, lexer.AppendString(`F], b: -`))
+	value, err = lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{json.Number("1"), json.Number("31")}, "b": json.Number("0")}, value)
+	assert.Nil(t, lexer.AppendString(`5}`))
+	value, err = lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{json.Number("1"), json.Number("31")}, "b": json.Number("-5")}, value)
+
+	// the atomic policies apply to the numbers kept
+	lexer = NewLexer(WithRelaxed(), WithAtomicValues(CompleteOmit))
+	assert.Nil(t, lexer.AppendString(`{a: 1, b: 0x1`))
+	assert.Equal(t, `{"a": 1}`, lexer.CompleteJSON())
+	value, err = lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": json.Number("1")}, value)
+}
+
+func TestWithRelaxed_numbersStreaming(t *testing.T) {
+	stream := `{hex: 0x1F, plus: +1, lead: .5, trail: 5., exp: -5.e+3, inf: -Infinity, nan: NaN, list: [0, -0x0A,],}`
+	lexer := NewLexer(WithRelaxed(), WithStrict())
+	for i := 0; i < len(stream); i++ {
+		assert.Nil(t, lexer.AppendString(stream[i:i+1]), "unexpected error at: %s", stream[:i+1])
+		completedJSON := lexer.CompleteJSON()
+		if completedJSON != `` {
+			assert.True(t, json.Valid([]byte(completedJSON)), "invalid completion %s of: %s", completedJSON, stream[:i+1])
+		}
+	}
+	value, err := lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"hex":   json.Number("31"),
+		"plus":  json.Number("1"),
+		"lead":  json.Number("0.5"),
+		"trail": json.Number("5.0"),
+		"exp":   json.Number("-5.0e+3"),
+		"inf":   nil,
+		"nan":   nil,
+		"list":  []interface{}{json.Number("0"), json.Number("-10")},
+	}, value)
+}
@@ -0,0 +1,84 @@
+package streamingjsongo
+
+// escape character of control characters in strings, like `\n`, others are escaped as unicode escapes like `\u001f`
+var controlCharacterEscapes = map[byte]byte{
+	'\b': 'b',
+	'\f': 'f',
+	'\n': 'n',
+	'\r': 'r',
+	'\t': 't',
+}
+
+// string repairer repairs strings of model output byte by byte: control characters are escaped, also after `\`,
+// and interior quotes are escaped, a quote is interior if it is not followed by `,`, `}`, `]` or `:`
+type stringRepairer struct {
+	quoteHeld bool   // a quote in a string is kept until the next token tells if it closes the string
+	pending   []byte // whitespace after the quote kept
+}
+
+// reset repairer for a new JSON stream
+func (repairer *stringRepairer) reset() {
+	repairer.quoteHeld = false
+	repairer.pending = repairer.pending[:0]
+}
+
+// lex a byte in a string, control characters are escaped
+func (lexer *Lexer) repairStringByte(c byte, position streamPosition) *SyntaxError {
+	if c >= 0x20 {
+		return lexer.lexRepaired(position, c)
+	}
+	if escaped, ok := controlCharacterEscapes[c]; ok {
+		return lexer.lexRepaired(position, TOKEN_ESCAPE_CHARACTER_SYMBOL, escaped)
+	}
+	const hexDigits = "0123456789abcdef"
+	return lexer.lexRepaired(position, TOKEN_ESCAPE_CHARACTER_SYMBOL, TOKEN_ALPHABET_LOWERCASE_U_SYMBOL, '0', '0', hexDigits[c>>4], hexDigits[c&0x0f])
+}
+
+// repair a byte of strings and lex it
+func (lexer *Lexer) repairByte(c byte, position streamPosition) *SyntaxError {
+	repairer := &lexer.repair
+	if repairer.quoteHeld {
+		if isIgnoreToken(c) {
+			repairer.pending = append(repairer.pending, c)
+			return nil
+		}
+		repairer.quoteHeld = false
+		pending := repairer.pending
+		repairer.pending = pending[:0]
+		switch c {
+		case TOKEN_COMMA_SYMBOL, TOKEN_RIGHT_BRACE_SYMBOL, TOKEN_RIGHT_BRACKET_SYMBOL, TOKEN_COLON_SYMBOL:
+			// the quote closes the string
+			if syntaxError := lexer.lexRepaired(position, TOKEN_QUOTE_SYMBOL); syntaxError != nil {
+				return syntaxError
+			}
+			if syntaxError := lexer.lexRepaired(position, pending...); syntaxError != nil {
+				return syntaxError
+			}
+			return lexer.lexRepaired(position, c)
+		}
+		// an interior quote, the whitespace after it is in the string
+		if syntaxError := lexer.lexRepaired(position, TOKEN_ESCAPE_CHARACTER_SYMBOL, TOKEN_QUOTE_SYMBOL); syntaxError != nil {
+			return syntaxError
+		}
+		for _, b := range pending {
+			if syntaxError := lexer.repairStringByte(b, position); syntaxError != nil {
+				return syntaxError
+			}
+		}
+	}
+	if c < 0x20 && lexer.streamStoppedInAStringOrEscape() && lexer.getTopTokenOnStack() == TOKEN_ESCAPE_CHARACTER {
+		// a control character can not be escaped, the escape character before it is kept as an escaped backslash
+		if syntaxError := lexer.lexRepaired(position, TOKEN_ESCAPE_CHARACTER_SYMBOL); syntaxError != nil {
+			return syntaxError
+		}
+		return lexer.repairStringByte(c, position)
+	}
+	if !lexer.streamStoppedInAString() {
+		return lexer.lexRepaired(position, c)
+	}
+	if c == TOKEN_QUOTE_SYMBOL {
+		repairer.quoteHeld = true
+		return nil
+	}
+	return lexer.repairStringByte(c, position)
+}
@@ -0,0 +1,58 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRepair(t *testing.T) {
+	streamingJSONCase := map[string]string{
+		"{\"a\":\"line1\nline2\ttab\"}": `{"a":"line1\nline2\ttab"}`,
+		"[\"bell\x07\"]":                `["bell\u0007"]`,
+		`{"say":"a "b" c"}`:             `{"say":"a \"b\" c"}`,
+		`{"say "hi"": 1}`:               `{"say \"hi\"": 1}`,
+		`["a" , "b"]`:                   `["a" , "b"]`,
+		`{"a":"x" }`:                    `{"a":"x" }`,
+		`{"a":"x"`:                      `{"a":"x"}`,
+		`{"a":"x" y`:                    `{"a":"x\" y"}`,
+		"{\"a\":\"x\" \n":               `{"a":"x"}`,
+		"{\"a\":\"x\" \ny":              `{"a":"x\" \ny"}`,
+		"{\"a\":1,\n\"b\":\"c\"}":       "{\"a\":1,\n\"b\":\"c\"}",
+		`{"a":"\"quoted\""}`:            `{"a":"\"quoted\""}`,
+		"{\"a\":\"x\\\ny\"}":            `{"a":"x\\\ny"}`,
+		"[\"x\\\t":                      `["x\\\t"]`,
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer(WithRepair())
+		assert.Nil(t, lexer.AppendString(testCase))
+		assert.Equal(t, expect, lexer.CompleteJSON(), "unexpected completion in case: %q", testCase)
+	}
+}
+
+func TestWithRepair_escapedControlCharacter(t *testing.T) {
+	lexer := NewLexer(WithRepair(), WithStrict())
+	assert.Nil(t, lexer.AppendString("{\"a\":\"x\\\ny\"}"))
+	assert.True(t, json.Valid([]byte(lexer.CompleteJSON())))
+	value, err := lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "x\\\ny"}, value)
+}
+
+func TestWithRepair_streaming(t *testing.T) {
+	stream := "{\"title\": \"The \"best\" day\", \"body\": \"line 1\nline 2\", \"tags\": [\"a\", \"b\"]}"
+	lexer := NewLexer(WithRepair(), WithStrict())
+	for i := 0; i < len(stream); i++ {
+		assert.Nil(t, lexer.AppendString(stream[i:i+1]))
+		completedJSON := lexer.CompleteJSON()
+		assert.True(t, json.Valid([]byte(completedJSON)), "invalid completion %s of: %q", completedJSON, stream[:i+1])
+	}
+	value, err := lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"title": `The "best" day`,
+		"body":  "line 1\nline 2",
+		"tags":  []interface{}{"a", "b"},
+	}, value)
+}
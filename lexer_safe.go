@@ -0,0 +1,210 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// SafeLexer wraps a lexer by a mutex, so a goroutine can append the JSON stream while others complete it.
+// the results are copied out of the lexer, they never change with the following appends.
+// callbacks given by OnComplete(), OnUpdate(), OnDocument() and OnDocumentError() are called with the lexer locked,
+// they must not call methods of the SafeLexer
+type SafeLexer struct {
+	mutex sync.Mutex
+	lexer *Lexer
+}
+
+// Snapshot of a JSON stream, the completed JSON and the partial value are taken at once
+type Snapshot struct {
+	CompletedJSON string        // completed JSON like CompleteJSON() returns
+	Value         interface{}   // partial value like Value() returns, it is nil if Err is not nil
+	Err           error         // error returned by Value()
+	Context       StreamContext // context like Context() returns
+}
+
+// new safe lexer for streaming JSON input
+func NewSafeLexer(options ...Option) *SafeLexer {
+	return &SafeLexer{lexer: NewLexer(options...)}
+}
+
+// run f with the lexer locked, like differ.Diff(lexer) or other methods not wrapped by the SafeLexer.
+// the lexer and the results owned by it must not be used after f returned
+func (safe *SafeLexer) Do(f func(lexer *Lexer)) {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	f(safe.lexer)
+}
+
+// reset the lexer for a new JSON stream
+func (safe *SafeLexer) Reset() {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	safe.lexer.Reset()
+}
+
+// append JSON string to current JSON stream content
+func (safe *SafeLexer) AppendString(str string) error {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return safe.lexer.AppendString(str)
+}
+
+// append JSON bytes to current JSON stream content
+func (safe *SafeLexer) AppendBytes(b []byte) error {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return safe.lexer.AppendBytes(b)
+}
+
+// implements io.Writer, appends p to current JSON stream content
+func (safe *SafeLexer) Write(p []byte) (int, error) {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return safe.lexer.Write(p)
+}
+
+// implements io.StringWriter, appends s to current JSON stream content
+func (safe *SafeLexer) WriteString(s string) (int, error) {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return safe.lexer.WriteString(s)
+}
+
+// complete the incomplete JSON string
+func (safe *SafeLexer) CompleteJSON() string {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return safe.lexer.CompleteJSON()
+}
+
+// complete the incomplete JSON string and append it to dst, returns the extended buffer
+func (safe *SafeLexer) CompleteJSONTo(dst []byte) []byte {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return safe.lexer.CompleteJSONTo(dst)
+}
+
+// complete the incomplete JSON string incrementally, prev must be the result of the previous call, or nil for the first call
+func (safe *SafeLexer) CompleteJSONIncremental(prev []byte) []byte {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return safe.lexer.CompleteJSONIncremental(prev)
+}
+
+// complete the incomplete JSON string by options
+func (safe *SafeLexer) CompleteJSONWith(opts CompletionOptions) string {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return safe.lexer.CompleteJSONWith(opts)
+}
+
+// write the completed JSON to w, w is called with the lexer locked
+func (safe *SafeLexer) WriteCompletedTo(w io.Writer) (int64, error) {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return safe.lexer.WriteCompletedTo(w)
+}
+
+// get a deep copy of the partial value of JSON stream
+func (safe *SafeLexer) Value() (interface{}, error) {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	value, err := safe.lexer.Value()
+	if err != nil {
+		return nil, err
+	}
+	return copyValue(value, false), nil
+}
+
+// decode the partial value of JSON stream into v like json.Unmarshal() does
+func (safe *SafeLexer) DecodePartial(v interface{}) error {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return safe.lexer.DecodePartial(v)
+}
+
+// get the current context of JSON stream
+func (safe *SafeLexer) Context() StreamContext {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return safe.lexer.Context()
+}
+
+// get a snapshot of JSON stream, the completed JSON, the partial value and the context are consistent with each other
+func (safe *SafeLexer) Snapshot() Snapshot {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	snapshot := Snapshot{CompletedJSON: safe.lexer.CompleteJSON(), Context: safe.lexer.Context()}
+	value, err := safe.lexer.Value()
+	if err != nil {
+		snapshot.Err = err
+		return snapshot
+	}
+	snapshot.Value = copyValue(value, false)
+	return snapshot
+}
+
+// get a copy of the stable prefix of JSON stream
+func (safe *SafeLexer) StablePrefix() []byte {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return append([]byte(nil), safe.lexer.StablePrefix()...)
+}
+
+// get a copy of the volatile tail of JSON stream
+func (safe *SafeLexer) VolatileTail() []byte {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return append([]byte(nil), safe.lexer.VolatileTail()...)
+}
+
+// take a copy of the stable bytes which are not taken yet
+func (safe *SafeLexer) TakeStable() []byte {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return append([]byte(nil), safe.lexer.TakeStable()...)
+}
+
+// get the documents completed in multiple documents mode
+func (safe *SafeLexer) Documents() []json.RawMessage {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return append([]json.RawMessage(nil), safe.lexer.Documents()...)
+}
+
+// make the string or number value at given JSON Pointer atomic
+func (safe *SafeLexer) SetAtomic(pointer string, policy CompletionPolicy) error {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return safe.lexer.SetAtomic(pointer, policy)
+}
+
+// subscribe to the completion of the value at given JSON Pointer, callback is called with the lexer locked
+func (safe *SafeLexer) OnComplete(pointer string, callback func(raw json.RawMessage)) error {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return safe.lexer.OnComplete(pointer, callback)
+}
+
+// subscribe to the updates of the value at given JSON Pointer, callback is called with the lexer locked,
+// the maps and slices in the value are owned by the lexer, they must not be used after callback returned
+func (safe *SafeLexer) OnUpdate(pointer string, callback func(value interface{})) error {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	return safe.lexer.OnUpdate(pointer, callback)
+}
+
+// set the callback of completed documents in multiple documents mode, callback is called with the lexer locked
+func (safe *SafeLexer) OnDocument(callback func(raw json.RawMessage)) {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	safe.lexer.OnDocument(callback)
+}
+
+// set the callback of dropped documents in multiple documents mode, callback is called with the lexer locked
+func (safe *SafeLexer) OnDocumentError(callback func(err *SyntaxError)) {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	safe.lexer.OnDocumentError(callback)
+}
@@ -0,0 +1,83 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeLexer_concurrent(t *testing.T) {
+	const appenders = 4
+	const elements = 200
+	safe := NewSafeLexer()
+	assert.Nil(t, safe.AppendString(`[`))
+
+	var appending sync.WaitGroup
+	for i := 0; i < appenders; i++ {
+		appending.Add(1)
+		go func() {
+			defer appending.Done()
+			for j := 0; j < elements; j++ {
+				assert.Nil(t, safe.AppendString(`{"a":"b"},`))
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	var reading sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		reading.Add(1)
+		go func() {
+			defer reading.Done()
+			var buffer []byte
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				assert.True(t, json.Valid([]byte(safe.CompleteJSON())))
+				buffer = safe.CompleteJSONTo(buffer[:0])
+				assert.True(t, json.Valid(buffer))
+				value, err := safe.Value()
+				assert.Nil(t, err)
+				if array, ok := value.([]interface{}); ok && len(array) > 0 {
+					// the copy is owned by the reader
+					array[0] = nil
+				}
+				snapshot := safe.Snapshot()
+				assert.Nil(t, snapshot.Err)
+				assert.Equal(t, decodeCompletedJSON(t, snapshot.CompletedJSON), snapshot.Value)
+				assert.True(t, strings.HasPrefix(snapshot.CompletedJSON, string(safe.StablePrefix()[:1])))
+				safe.Context()
+			}
+		}()
+	}
+
+	appending.Wait()
+	close(done)
+	reading.Wait()
+
+	assert.Nil(t, safe.AppendString(`null]`))
+	value, err := safe.Value()
+	assert.Nil(t, err)
+	assert.Len(t, value, appenders*elements+1)
+}
+
+func TestSafeLexer_Do(t *testing.T) {
+	safe := NewSafeLexer()
+	differ := NewDiffer()
+	assert.Nil(t, safe.AppendString(`{"a":"hel`))
+	var operations []PatchOperation
+	safe.Do(func(lexer *Lexer) {
+		var err error
+		operations, err = differ.Diff(lexer)
+		assert.Nil(t, err)
+	})
+	assert.Len(t, operations, 1)
+	assert.Equal(t, []byte(`{"a":"hel`), safe.TakeStable())
+	assert.Equal(t, []byte(`"}`), safe.VolatileTail())
+}
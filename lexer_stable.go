@@ -0,0 +1,52 @@
+package streamingjsongo
+
+// get end offset of the stable prefix in JSON content
+func (lexer *Lexer) stableEnd() int {
+	end := lexer.JSONContent.Len()
+	if lexer.grammarError != nil && int(lexer.grammarError.Offset) < end {
+		// the content after the first grammar error is not a JSON
+		end = int(lexer.grammarError.Offset)
+	}
+	if lexer.grammar.inNumberOrLiteral() && lexer.grammar.scalarStart < end {
+		// the partial number or literal, it is replaced by completion like `-` to `0` and `tr` to `true`
+		end = lexer.grammar.scalarStart
+	}
+	if hidden, ok := lexer.hiddenCompletion(); ok && hidden.cut < end {
+		// the dangling key or atomic value is hidden from the completed JSON
+		end = hidden.cut
+	}
+	if lexer.memberValueMayBeOmitted() {
+		// the key and colon are omitted with the atomic value, like `"a":` of `{"a":"x`
+		if memberCut := lexer.grammar.memberCuts[len(lexer.grammar.memberCuts)-1]; memberCut < end {
+			end = memberCut
+		}
+	}
+	return end
+}
+
+// get the stable prefix of JSON stream, the bytes are guaranteed never to change, they are the prefix of the final JSON.
+// placeholders and partial numbers or literals are not stable, like the stable prefix of `{"a":[1, tr` is `{"a":[1, `.
+// the returned bytes are owned by the lexer, they are valid until the next append
+func (lexer *Lexer) StablePrefix() []byte {
+	return lexer.JSONContent.Bytes()[:lexer.stableEnd()]
+}
+
+// get the volatile tail of JSON stream, it is the completed JSON after the stable prefix, it may change with the stream,
+// like the volatile tail of `{"a":[1, tr` is `true]}`. StablePrefix() followed by VolatileTail() is the completed JSON
+func (lexer *Lexer) VolatileTail() []byte {
+	content, mirrorTokens := lexer.completionParts()
+	volatileTail := append([]byte{}, content[lexer.stableEnd():]...)
+	return append(volatileTail, mirrorTokens...)
+}
+
+// take the stable bytes which are not taken yet, so they can be forwarded downstream as soon as they are stable.
+// the returned bytes are owned by the lexer, they are valid until the next append
+func (lexer *Lexer) TakeStable() []byte {
+	end := lexer.stableEnd()
+	if end < lexer.stableTaken {
+		return nil
+	}
+	stable := lexer.JSONContent.Bytes()[lexer.stableTaken:end]
+	lexer.stableTaken = end
+	return stable
+}
@@ -0,0 +1,74 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStablePrefix(t *testing.T) {
+	// the value is the stable prefix and the volatile tail
+	streamingJSONCase := map[string][2]string{
+		`{"a":`:           {`{"a":`, `null}`},
+		`{"a":[1, tr`:     {`{"a":[1, `, `true]}`},
+		`{"a":[1, -`:      {`{"a":[1, `, `0]}`},
+		`{"a":[1, -1.2e`:  {`{"a":[1, `, `-1.2]}`},
+		`{"a":[1, 2`:      {`{"a":[1, `, `2]}`},
+		`{"a":[1, 2 `:     {`{"a":[1, 2`, `]}`},
+		`{"a":"hello wor`: {`{"a":"hello wor`, `"}`},
+		`{"a":"x\u00`:     {`{"a":"x`, `"}`},
+		`{"a`:             {`{"a`, `":null}`},
+		`[nul`:            {`[`, `null]`},
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer()
+		assert.Nil(t, lexer.AppendString(testCase))
+		assert.Equal(t, expect[0], string(lexer.StablePrefix()), "unexpected stable prefix in case: %s", testCase)
+		assert.Equal(t, expect[1], string(lexer.VolatileTail()), "unexpected volatile tail in case: %s", testCase)
+	}
+}
+
+func TestTakeStable(t *testing.T) {
+	streamingJSONContent := `{"string": "这是一个字符串", "integer": 42, "float": 3.14159, "boolean_true": true, "null": null, "object": {"empty_object": {}, "array":["string in array", -123, 45.67e-3, false, {"k": "v"}, []]}}`
+	lexer := NewLexer()
+	var taken strings.Builder
+	for i := 0; i < len(streamingJSONContent); i++ {
+		assert.Nil(t, lexer.AppendString(streamingJSONContent[i:i+1]))
+		taken.Write(lexer.TakeStable())
+		stablePrefix := string(lexer.StablePrefix())
+		assert.Equal(t, stablePrefix, taken.String())
+		assert.True(t, strings.HasPrefix(streamingJSONContent, stablePrefix), "unstable prefix at: %s", streamingJSONContent[:i+1])
+		assert.Equal(t, lexer.CompleteJSON(), stablePrefix+string(lexer.VolatileTail()))
+	}
+	assert.Equal(t, streamingJSONContent, taken.String())
+
+	lexer.Reset()
+	assert.Nil(t, lexer.AppendString(`[1,`))
+	assert.Equal(t, `[1`, string(lexer.TakeStable()))
+	assert.Equal(t, ``, string(lexer.TakeStable()))
+}
+
+func TestTakeStable_omit(t *testing.T) {
+	streamingJSONContent := `{"id": 12, "url": "https://example.com", "tags": ["a", "b"], "meta": {"size": -1.5, "ok": true}}`
+	lexers := map[string]*Lexer{
+		"all":  NewLexer(WithAtomicValues(CompleteOmit)),
+		"path": NewLexer(),
+	}
+	assert.Nil(t, lexers["path"].SetAtomic("/url", CompleteOmit))
+	assert.Nil(t, lexers["path"].SetAtomic("/meta/size", CompleteOmit))
+	for name, lexer := range lexers {
+		var taken strings.Builder
+		for i := 0; i < len(streamingJSONContent); i++ {
+			assert.Nil(t, lexer.AppendString(streamingJSONContent[i:i+1]))
+			taken.Write(lexer.TakeStable())
+			completedJSON := taken.String() + string(lexer.VolatileTail())
+			if !assert.Equal(t, lexer.CompleteJSON(), completedJSON, "unexpected completion by %s at: %s", name, streamingJSONContent[:i+1]) {
+				break
+			}
+			assert.True(t, json.Valid([]byte(completedJSON)), "invalid completion %s by %s at: %s", completedJSON, name, streamingJSONContent[:i+1])
+		}
+		assert.Equal(t, streamingJSONContent, taken.String())
+	}
+}
@@ -1,7 +1,10 @@
 package streamingjsongo
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
 	"strings"
 	"testing"
 
@@ -38,7 +41,7 @@ func TestCompleteJSON_base(t *testing.T) {
 		`{"a":false , "b`:                    `{"a":false , "b":null}`,
 		`{"a":-`:                             `{"a":0}`,
 		`{"a":12`:                            `{"a":12}`,
-		`{"a":-0`:                            `{"a":-0}`, // @TODO: should be 0, not -0
+		`{"a":-0`:                            `{"a":-0}`,
 		`{"a":-12`:                           `{"a":-12}`,
 		`{"a":12,`:                           `{"a":12}`,
 		`{"a":12.`:                           `{"a":12.0}`,
@@ -189,7 +192,7 @@ func TestCompleteJSON_base(t *testing.T) {
 		`{"a":[]`:           `{"a":[]}`,
 		`{"a":[1`:           `{"a":[1]}`,
 		`{"a":[1,`:          `{"a":[1]}`,
-		`{"a":[-0,`:         `{"a":[-0]}`, // @TODO: should be 0, not -0
+		`{"a":[-0,`:         `{"a":[-0]}`,
 		`{"a":[-1,`:         `{"a":[-1]}`,
 		`{"a":[1,0`:         `{"a":[1,0]}`,
 		`{"a":[1,0.0`:       `{"a":[1,0.0]}`,
@@ -198,7 +201,7 @@ func TestCompleteJSON_base(t *testing.T) {
 		`{"a":[1,0.01]}`:    `{"a":[1,0.01]}`,
 		`{"a":[-1,0.01]}`:   `{"a":[-1,0.01]}`,
 		`{"a":[-1,-`:        `{"a":[-1,0]}`,
-		`{"a":[-1,-0`:       `{"a":[-1,-0]}`, // @TODO: should be 0, not -0
+		`{"a":[-1,-0`:       `{"a":[-1,-0]}`,
 		`{"a":[1,-0.01]}`:   `{"a":[1,-0.01]}`,
 		`{"a":[-1,-0.01]}`:  `{"a":[-1,-0.01]}`,
 		`{"a":[n`:           `{"a":[null]}`,
@@ -408,7 +411,7 @@ func TestCompleteJSON_base(t *testing.T) {
 		`[{"a":false}]`:              `[{"a":false}]`,
 		`[{"a":-`:                    `[{"a":0}]`,
 		`[{"a":0`:                    `[{"a":0}]`,
-		`[{"a":-0`:                   `[{"a":-0}]`, // @TODO: should be 0, not -0
+		`[{"a":-0`:                   `[{"a":-0}]`,
 		`[{"a":0.`:                   `[{"a":0.0}]`,
 		`[{"a":0.1`:                  `[{"a":0.1}]`,
 		`[{"a":0.10`:                 `[{"a":0.10}]`,
@@ -427,7 +430,7 @@ func TestCompleteJSON_base(t *testing.T) {
 		`[{"a":[{"b":"c"},{"`:        `[{"a":[{"b":"c"},{"":null}]}]`,
 		`[{"a":[{"b":"c"},{"d"`:      `[{"a":[{"b":"c"},{"d":null}]}]`,
 		`[{"a":[{"b":"c"},{"d":-`:    `[{"a":[{"b":"c"},{"d":0}]}]`,
-		`[{"a":[{"b":"c"},{"d":-0`:   `[{"a":[{"b":"c"},{"d":-0}]}]`, // @TODO: should be 0, not -0
+		`[{"a":[{"b":"c"},{"d":-0`:   `[{"a":[{"b":"c"},{"d":-0}]}]`,
 		`[{"a":[{"b":"c"},{"d":1.`:   `[{"a":[{"b":"c"},{"d":1.0}]}]`,
 		`[{"a":[{"b":"c"},{"d":1.1`:  `[{"a":[{"b":"c"},{"d":1.1}]}]`,
 		`[{"a":[{"b":"c"},{"d":-1.1`: `[{"a":[{"b":"c"},{"d":-1.1}]}]`,
@@ -600,3 +603,170 @@ func TestCompleteJSON_escapeAndEtc(t *testing.T) {
 	}
 
 }
+
+func TestAppendBytes(t *testing.T) {
+	lexer := NewLexer()
+	assert.Nil(t, lexer.AppendBytes([]byte(`{"a":[tr`)))
+	assert.Equal(t, `{"a":[true]}`, lexer.CompleteJSON())
+	assert.Nil(t, lexer.AppendBytes([]byte(`ue], "b": "c`)))
+	assert.Equal(t, `{"a":[true], "b": "c"}`, lexer.CompleteJSON())
+}
+
+func TestWrite(t *testing.T) {
+	streamingJSONContent := `{"function_name":"run_code", "arguments": "print(\"hello world\")"}`
+	lexer := NewLexer()
+	var tee bytes.Buffer
+	written, errInCopy := io.Copy(io.MultiWriter(lexer, &tee), strings.NewReader(streamingJSONContent))
+	assert.Nil(t, errInCopy)
+	assert.Equal(t, int64(len(streamingJSONContent)), written)
+	assert.Equal(t, streamingJSONContent, lexer.CompleteJSON())
+	assert.Equal(t, streamingJSONContent, tee.String())
+
+	// io.StringWriter
+	lexer = NewLexer()
+	n, errInWriteString := io.WriteString(lexer, `[1, "a`)
+	assert.Nil(t, errInWriteString)
+	assert.Equal(t, 6, n)
+	assert.Equal(t, `[1, "a"]`, lexer.CompleteJSON())
+}
+
+func TestWrite_error(t *testing.T) {
+	lexer := NewLexer(WithStrict())
+	n, errInWrite := lexer.Write([]byte(`{"a":1]`))
+	assert.Equal(t, 6, n)
+
+	var syntaxError *SyntaxError
+	if assert.True(t, errors.As(errInWrite, &syntaxError)) {
+		assert.Equal(t, `{"a":1]`, syntaxError.Excerpt)
+		assert.Equal(t, int64(6), syntaxError.Offset)
+	}
+
+	lexer = NewLexer(WithStrict())
+	n, errInWrite = lexer.WriteString(`[1 2]`)
+	assert.Equal(t, 3, n)
+	assert.NotNil(t, errInWrite)
+	assert.Equal(t, `2]`, lexer.JSONSegment)
+}
+
+func TestJSONSegment(t *testing.T) {
+	// the segment is consumed by lexing
+	lexer := NewLexer()
+	assert.Nil(t, lexer.AppendString(`{"a":`))
+	assert.Equal(t, ``, lexer.JSONSegment)
+	var rests []string
+	assert.Nil(t, lexer.OnComplete("/a", func(raw json.RawMessage) {
+		rests = append(rests, lexer.JSONSegment)
+	}))
+	assert.Nil(t, lexer.AppendString(`true, "b": 2}`))
+	assert.Equal(t, ``, lexer.JSONSegment)
+	assert.Equal(t, []string{`, "b": 2}`}, rests)
+}
+
+func TestAppendBytes_allocs(t *testing.T) {
+	streamingJSONContent := []byte(`{"string": "这是一个字符串", "integer": 42, "array":["string in array", 45.67, true, null, {"a": "b"},["c"]]}`)
+	lexer := NewLexer()
+	allocs := testing.AllocsPerRun(100, func() {
+		lexer.Reset()
+		if err := lexer.AppendBytes(streamingJSONContent); err != nil {
+			panic(err)
+		}
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func TestCompleteJSONTo(t *testing.T) {
+	streamingJSONContent := `{"a":[1, -2.5e3, true, false, null, {"b": "c\"dA"}], "e": {"f": []}}`
+	lexer := NewLexer()
+	var completedJSON []byte
+	var writtenJSON bytes.Buffer
+	for i := 0; i < len(streamingJSONContent); i++ {
+		assert.Nil(t, lexer.AppendString(streamingJSONContent[i:i+1]))
+		expect := lexer.CompleteJSON()
+
+		completedJSON = lexer.CompleteJSONTo(completedJSON[:0])
+		assert.Equal(t, expect, string(completedJSON))
+
+		writtenJSON.Reset()
+		written, errInWrite := lexer.WriteCompletedTo(&writtenJSON)
+		assert.Nil(t, errInWrite)
+		assert.Equal(t, int64(len(expect)), written)
+		assert.Equal(t, expect, writtenJSON.String())
+	}
+}
+
+func TestCompleteJSONTo_allocs(t *testing.T) {
+	lexer := NewLexer()
+	assert.Nil(t, lexer.AppendString(`{"a":[{"b":"c`))
+	completedJSON := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		completedJSON = lexer.CompleteJSONTo(completedJSON[:0])
+	})
+	assert.Equal(t, float64(0), allocs)
+	assert.Equal(t, `{"a":[{"b":"c"}]}`, string(completedJSON))
+
+	// the partial number is completed into dst
+	streamingJSONCase := map[string]string{
+		`{"a":12`:  `{"a":12}`,
+		`{"a":1.`:  `{"a":1.0}`,
+		`[-1.5e+`:  `[-1.5e0]`,
+		`{"a":"x`:  `{"a":"x"}`,
+		`{"a":[tr`: `{"a":[true]}`,
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer()
+		assert.Nil(t, lexer.AppendString(testCase))
+		completedJSON = lexer.CompleteJSONTo(completedJSON[:0])
+		allocs := testing.AllocsPerRun(100, func() {
+			completedJSON = lexer.CompleteJSONTo(completedJSON[:0])
+		})
+		assert.Equal(t, float64(0), allocs, "unexpected allocation in case: %s", testCase)
+		assert.Equal(t, expect, string(completedJSON), "unexpected completion in case: %s", testCase)
+	}
+}
+
+func TestCompleteJSONIncremental(t *testing.T) {
+	streamingJSONContent := `{"string": "这是一个字符串", "integer": 42, "float": -3.14159e-2, "boolean_true": true, "null": null, "object": {"empty_object": {}, "array":["string in array", -123, false, {"k": "v"}, []]}}`
+	lexer := NewLexer()
+	var completedJSON []byte
+	for i := 0; i < len(streamingJSONContent); i++ {
+		assert.Nil(t, lexer.AppendString(streamingJSONContent[i:i+1]))
+		completedJSON = lexer.CompleteJSONIncremental(completedJSON)
+		assert.Equal(t, lexer.CompleteJSON(), string(completedJSON), "unexpected completion at: %s", streamingJSONContent[:i+1])
+	}
+
+	// a prev shorter than the stable prefix kept starts over
+	assert.Equal(t, streamingJSONContent, string(lexer.CompleteJSONIncremental(nil)))
+}
+
+func TestCompleteJSONIncremental_work(t *testing.T) {
+	// the stable prefix in prev is never copied again, so the work of each call only depends on the new bytes
+	for _, documentLen := range []int{1 << 10, 1 << 20} {
+		lexer := NewLexer()
+		assert.Nil(t, lexer.AppendString(`{"padding":"`+strings.Repeat("x", documentLen)+`","items":[`))
+		completedJSON := lexer.CompleteJSONIncremental(nil)
+		for i := 0; i < 100; i++ {
+			stableLen := len(lexer.StablePrefix())
+			for j := 0; j < stableLen; j++ {
+				// poison the stable prefix kept, it must not be written by the next call
+				completedJSON[j] = '#'
+			}
+			assert.Nil(t, lexer.AppendString(`{"id":12},`))
+			completedJSON = lexer.CompleteJSONIncremental(completedJSON)
+			assert.Equal(t, strings.Repeat("#", stableLen), string(completedJSON[:stableLen]))
+			assert.Equal(t, lexer.CompleteJSON()[stableLen:], string(completedJSON[stableLen:]))
+		}
+	}
+}
+
+func TestTokenStack_depth(t *testing.T) {
+	lexer := NewLexer()
+	assert.Nil(t, lexer.AppendString(`{"a":[`))
+	for i := 0; i < 1000; i++ {
+		assert.Nil(t, lexer.AppendString(`{"b":[1, -2.5, "c", true, null, {"d": {}}], "e": "f"}, `))
+		assert.True(t, len(lexer.TokenStack) <= 6, "token stack should only hold open containers and the member in lexing")
+	}
+	assert.Equal(t, []int{TOKEN_LEFT_BRACE, TOKEN_QUOTE, TOKEN_QUOTE, TOKEN_COLON, TOKEN_LEFT_BRACKET, TOKEN_COMMA}, lexer.TokenStack)
+	assert.Nil(t, lexer.AppendString(`1]}`))
+	assert.Equal(t, 0, len(lexer.TokenStack))
+	assert.Equal(t, 0, len(lexer.MirrorTokenStack))
+}
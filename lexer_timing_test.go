@@ -2,6 +2,7 @@ package streamingjsongo
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -13,6 +14,9 @@ func BenchmarkParse(b *testing.B) {
 	b.Run("streaming-json-go-append-and-complete-json-segment", func(b *testing.B) {
 		benchmarkAppendAndCompleteJSON(b, testCaseA)
 	})
+	b.Run("streaming-json-go-append-json-segment-with-pool", func(b *testing.B) {
+		benchmarkAppendStringWithPool(b, testCaseA)
+	})
 
 }
 
@@ -46,3 +50,80 @@ func benchmarkAppendAndCompleteJSON(b *testing.B, s string) {
 		}
 	})
 }
+
+func benchmarkAppendStringWithPool(b *testing.B, s string) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(s)))
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			lexer := AcquireLexer()
+			if err := lexer.AppendString(s); err != nil {
+				panic(fmt.Errorf("unexpected error: %s", err))
+			}
+			ReleaseLexer(lexer)
+		}
+	})
+}
+
+func BenchmarkCompleteJSON(b *testing.B) {
+	// about 50 KB tool-call arguments streamed in 5-byte chunks, complete the JSON after every chunk
+	testCaseA := `{"items":[` + strings.Repeat(`{"name":"streaming-json-go","score":3.14159,"tags":["a","b"]},`, 800) + `{}]}`
+	b.Run("streaming-json-go-complete-json-per-chunk", func(b *testing.B) {
+		benchmarkCompleteJSONPerChunk(b, testCaseA, 5, func(lexer *Lexer, dst []byte) []byte {
+			return append(dst[:0], lexer.CompleteJSON()...)
+		})
+	})
+	b.Run("streaming-json-go-complete-json-to-per-chunk", func(b *testing.B) {
+		benchmarkCompleteJSONPerChunk(b, testCaseA, 5, func(lexer *Lexer, dst []byte) []byte {
+			return lexer.CompleteJSONTo(dst[:0])
+		})
+	})
+}
+
+func benchmarkCompleteJSONPerChunk(b *testing.B, s string, chunkSize int, complete func(lexer *Lexer, dst []byte) []byte) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(s)))
+	lexer := NewLexer()
+	var completedJSON []byte
+	for i := 0; i < b.N; i++ {
+		lexer.Reset()
+		for offset := 0; offset < len(s); offset += chunkSize {
+			end := offset + chunkSize
+			if end > len(s) {
+				end = len(s)
+			}
+			if err := lexer.AppendString(s[offset:end]); err != nil {
+				panic(fmt.Errorf("unexpected error: %s", err))
+			}
+			completedJSON = complete(lexer, completedJSON)
+		}
+	}
+}
+
+func BenchmarkTokenStack(b *testing.B) {
+	// multi-megabyte NDJSON-like array of objects, the token stack should not grow with input size
+	testCaseA := `[` + strings.Repeat(`{"id":12345,"name":"streaming-json-go","ok":true,"tags":["a","b"],"next":null},`, 50000) + `{}]`
+	b.Run("streaming-json-go-append-large-array", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(testCaseA)))
+		lexer := NewLexer()
+		maxTokenStackLen := 0
+		for i := 0; i < b.N; i++ {
+			lexer.Reset()
+			for offset := 0; offset < len(testCaseA); offset += 4096 {
+				end := offset + 4096
+				if end > len(testCaseA) {
+					end = len(testCaseA)
+				}
+				if err := lexer.AppendString(testCaseA[offset:end]); err != nil {
+					panic(fmt.Errorf("unexpected error: %s", err))
+				}
+				if len(lexer.TokenStack) > maxTokenStackLen {
+					maxTokenStackLen = len(lexer.TokenStack)
+				}
+			}
+		}
+		b.ReportMetric(float64(maxTokenStackLen), "max-stack-len")
+		b.ReportMetric(float64(cap(lexer.TokenStack)), "stack-cap")
+	})
+}
@@ -0,0 +1,126 @@
+package streamingjsongo
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompleteJSON_unicodeEscape(t *testing.T) {
+	streamingJSONCase := map[string]string{
+		`"\u`:                 `""`,
+		`"\u00`:               `""`,
+		`"\u00e9`:             `"\u00e9"`,
+		`"\ud83d`:             `""`,
+		`"\ud83d\`:            `""`,
+		`"\ud83d\u`:           `""`,
+		`"\ud83d\ude`:         `""`,
+		`"\ud83d\ude00`:       `"\ud83d\ude00"`,
+		`"\ud83da`:            `"\ufffda"`,
+		`"\ud83d"`:            `"\ufffd"`,
+		`"\ud83d\n`:           `"\ufffd\n"`,
+		`"\ud83d\u00e9`:       `"\ufffd\u00e9"`,
+		`"\ud83d\ud83d\ude00`: `"\ufffd\ud83d\ude00"`,
+		`"\ude00`:             `"\ufffd"`,
+		`{"a":"\ud83d`:        `{"a":""}`,
+		`["\ud83d\ude00",`:    `["\ud83d\ude00"]`,
+		`"\u00zz`:             `"zz"`,
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer()
+		assert.Nil(t, lexer.AppendString(testCase))
+		assert.Equal(t, expect, lexer.CompleteJSON(), "unexpected completion in case: %s", testCase)
+	}
+}
+
+func TestValue_surrogatePairAcrossSegments(t *testing.T) {
+	lexer := NewLexer()
+	assert.Nil(t, lexer.AppendString(`{"a":"smile \ud83d`))
+	value, err := lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "smile "}, value)
+	assert.Nil(t, lexer.AppendString(`\ude00"}`))
+	value, err = lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "smile \U0001F600"}, value)
+}
+
+func TestValue_unicodeEscapeRuneByRune(t *testing.T) {
+	streams := []string{
+		`["😀 é中 \ud83d\ude00"]`,
+		`["\ud83d", "\ude00", "\ud83d\u00e9", "\ud83d\n", "\ud83d\ud83d\ude00"]`,
+		`{"😀":"\ud83d😀"}`,
+	}
+	for _, stream := range streams {
+		lexer := NewLexer()
+		for i, r := range stream {
+			end := i + utf8.RuneLen(r)
+			assert.Nil(t, lexer.AppendString(stream[i:end]))
+			completedJSON := lexer.CompleteJSON()
+			assert.True(t, utf8.ValidString(completedJSON), "invalid UTF-8 in completion: %s", completedJSON)
+			value, err := lexer.Value()
+			assert.Nil(t, err)
+			assert.Equal(t, decodeCompletedJSON(t, completedJSON), value, "unexpected value of: %s", stream[:end])
+		}
+	}
+}
+
+func TestWithStrict_unicodeEscape(t *testing.T) {
+	invalidJSONCase := []string{`"\u00zz"`, `"\ug`, `"\u12 `, `["\u123"]`}
+	for _, testCase := range invalidJSONCase {
+		lexer := NewLexer(WithStrict())
+		assert.NotNil(t, lexer.AppendString(testCase), "expected error in case: %s", testCase)
+	}
+}
+
+func TestCompleteJSON_runeAcrossSegments(t *testing.T) {
+	streams := []string{
+		`{"a":"这是一个字符串"}`,
+		`["😀", "é", {"中文":"😀\ud83d\ude00"}]`,
+	}
+	for _, stream := range streams {
+		lexer := NewLexer()
+		for i := 0; i < len(stream); i++ {
+			assert.Nil(t, lexer.AppendString(stream[i:i+1]))
+			completedJSON := lexer.CompleteJSON()
+			assert.True(t, utf8.ValidString(completedJSON), "invalid UTF-8 in completion: %q", completedJSON)
+			value, err := lexer.Value()
+			assert.Nil(t, err)
+			assert.Equal(t, decodeCompletedJSON(t, completedJSON), value, "unexpected value of: %q", stream[:i+1])
+		}
+		assert.Equal(t, stream, lexer.CompleteJSON())
+	}
+}
+
+func TestCompleteJSON_invalidRune(t *testing.T) {
+	streamingJSONCase := map[string]string{
+		"\"\xe4":         `""`,
+		"\"\xe4\xb8":     `""`,
+		"\"\xe4a":        "\"\xe4a\"",
+		"\"\xe4\xb8\"":   "\"\xe4\xb8\"",
+		"\"\xff":         "\"\xff\"",
+		"\"\xe4\xe4\xb8": "\"\xe4\"",
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer()
+		assert.Nil(t, lexer.AppendString(testCase))
+		assert.Equal(t, expect, lexer.CompleteJSON(), "unexpected completion in case: %q", testCase)
+	}
+}
+
+func TestWithValidUTF8(t *testing.T) {
+	invalidJSONCase := []string{"\"\xff", "\"\x80", "\"\xe4a", "\"\xe4\"", "\"\xe0\x80", "\"\xed\xa0\x80", "{\"\xc0\xaf\":1}"}
+	for _, testCase := range invalidJSONCase {
+		lexer := NewLexer(WithValidUTF8())
+		assert.NotNil(t, lexer.AppendString(testCase), "expected error in case: %q", testCase)
+	}
+
+	// the rejected byte changes nothing
+	lexer := NewLexer(WithValidUTF8())
+	assert.Nil(t, lexer.AppendString("[\"中\xe4"))
+	assert.NotNil(t, lexer.AppendString("a"))
+	assert.Equal(t, `["中"]`, lexer.CompleteJSON())
+	assert.Nil(t, lexer.AppendString("\xb8\xad\"]"))
+	assert.Equal(t, `["中中"]`, lexer.CompleteJSON())
+}
@@ -0,0 +1,447 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// open container of partial value
+type valueFrame struct {
+	object map[string]interface{} // open object, nil if the container is an array
+	array  []interface{}          // open array
+	key    string                 // key of the member in lexing, for objects only
+}
+
+// value builder builds the partial value of JSON stream incrementally by grammar notifications
+type valueBuilder struct {
+	root   interface{}     // top-level value
+	frames []valueFrame    // open containers, the innermost one is at the end
+	str    strings.Builder // decoded content of the string or key in lexing
+	number []byte          // content of the number in lexing
+
+	inString      bool // a string or key is in lexing
+	inKey         bool // the string in lexing is an object key
+	inNumber      bool // a number is in lexing
+	inLiteral     bool // a literal is in lexing
+	awaitingValue bool // the key of the innermost object is complete, and its value is not started yet
+
+	pendingKey         string      // partial key stored into the innermost object by flush()
+	pendingKeyStored   bool        // if the partial key is stored
+	pendingKeyPrevious interface{} // value of the same key before the partial key stored
+	pendingKeyExisted  bool        // if the same key existed before the partial key stored
+	omitPendingKey     bool        // the partial key is not stored, for WithOmitDanglingKeys()
+	keyPrevious        interface{} // value of the same key before the key of the member in lexing completed
+	keyExisted         bool        // if the same key existed before the key of the member in lexing completed
+	heldStored         bool        // the value held by the stages before lexing is stored by storeHeldValue()
+
+	atomicPolicy CompletionPolicy // policy of all atomic values given by WithAtomicValues()
+	atomicPaths  []atomicPath     // atomic values at paths given by SetAtomic()
+	scalarPolicy CompletionPolicy // policy of the string or number value in lexing, CompleteKeep if it is not atomic
+}
+
+// store value into the slot of current value
+func (builder *valueBuilder) set(value interface{}) {
+	framesLen := len(builder.frames)
+	if framesLen == 0 {
+		builder.root = value
+		return
+	}
+	frame := &builder.frames[framesLen-1]
+	if frame.object != nil {
+		frame.object[frame.key] = value
+		return
+	}
+	frame.array[len(frame.array)-1] = value
+}
+
+// add a new value into the innermost container, or as the top-level value
+func (builder *valueBuilder) add(value interface{}) {
+	builder.awaitingValue = false
+	framesLen := len(builder.frames)
+	if framesLen == 0 {
+		builder.root = value
+		return
+	}
+	frame := &builder.frames[framesLen-1]
+	if frame.object != nil {
+		frame.object[frame.key] = value
+		return
+	}
+	frame.array = append(frame.array, value)
+	builder.storeArray(framesLen - 1)
+}
+
+// store the array of given frame into its parent slot, the slice header changes on every append
+func (builder *valueBuilder) storeArray(frameIndex int) {
+	array := builder.frames[frameIndex].array
+	if frameIndex == 0 {
+		builder.root = array
+		return
+	}
+	parent := &builder.frames[frameIndex-1]
+	if parent.object != nil {
+		parent.object[parent.key] = array
+		return
+	}
+	parent.array[len(parent.array)-1] = array
+}
+
+// remove the partial key stored by flush(), and restore the value it replaced
+func (builder *valueBuilder) removePendingKey() {
+	if !builder.pendingKeyStored {
+		return
+	}
+	object := builder.frames[len(builder.frames)-1].object
+	if builder.pendingKeyExisted {
+		object[builder.pendingKey] = builder.pendingKeyPrevious
+	} else {
+		delete(object, builder.pendingKey)
+	}
+	builder.pendingKeyStored = false
+	builder.pendingKeyPrevious = nil
+}
+
+// store the partial key into the innermost object with null value, like `{"a` completes to `{"a":null}`
+func (builder *valueBuilder) storePendingKey() {
+	builder.removePendingKey()
+	object := builder.frames[len(builder.frames)-1].object
+	builder.pendingKey = builder.str.String()
+	builder.pendingKeyPrevious, builder.pendingKeyExisted = object[builder.pendingKey]
+	builder.pendingKeyStored = true
+	object[builder.pendingKey] = nil
+}
+
+// store the value held by the stages before lexing into the innermost slot, like `-` of `-Infinity` completes to `0`
+func (builder *valueBuilder) storeHeldValue(value interface{}, policy CompletionPolicy) {
+	builder.removeHeldValue()
+	if policy == CompleteNull || policy == CompleteOmit {
+		// the omitted top-level value completes to null
+		value = nil
+	}
+	framesLen := len(builder.frames)
+	if framesLen == 0 {
+		builder.root = value
+		builder.heldStored = true
+		return
+	}
+	frame := &builder.frames[framesLen-1]
+	switch {
+	case frame.object != nil && policy == CompleteOmit:
+		// the member is omitted, the value of the same key before it is restored
+		if builder.keyExisted {
+			frame.object[frame.key] = builder.keyPrevious
+		} else {
+			delete(frame.object, frame.key)
+		}
+	case frame.object != nil:
+		frame.object[frame.key] = value
+	case policy == CompleteOmit:
+		return
+	default:
+		frame.array = append(frame.array, value)
+		builder.storeArray(framesLen - 1)
+	}
+	builder.heldStored = true
+}
+
+// remove the held value stored by storeHeldValue(), before the stream changes the value
+func (builder *valueBuilder) removeHeldValue() {
+	if !builder.heldStored {
+		return
+	}
+	builder.heldStored = false
+	framesLen := len(builder.frames)
+	if framesLen == 0 {
+		builder.root = nil
+		return
+	}
+	frame := &builder.frames[framesLen-1]
+	if frame.object != nil {
+		// the key awaits its value
+		frame.object[frame.key] = nil
+		return
+	}
+	frame.array = frame.array[:len(frame.array)-1]
+	builder.storeArray(framesLen - 1)
+}
+
+// get the atomic policy of the value starting in the innermost slot
+func (builder *valueBuilder) startPolicy() CompletionPolicy {
+	if builder.atomicPolicy != CompleteKeep {
+		return builder.atomicPolicy
+	}
+	for i := range builder.atomicPaths {
+		if builder.matchStartPath(&builder.atomicPaths[i]) {
+			return builder.atomicPaths[i].policy
+		}
+	}
+	return CompleteKeep
+}
+
+// check if the path of the value starting in the innermost slot matches the atomic path
+func (builder *valueBuilder) matchStartPath(path *atomicPath) bool {
+	framesLen := len(builder.frames)
+	if len(path.tokens) != framesLen {
+		return false
+	}
+	for i := 0; i < framesLen; i++ {
+		frame := &builder.frames[i]
+		if frame.object != nil {
+			if frame.key != path.tokens[i] {
+				return false
+			}
+			continue
+		}
+		// the value starting is not added into the innermost array yet
+		index := len(frame.array) - 1
+		if i == framesLen-1 {
+			index++
+		}
+		if index != path.indexes[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// start a string or number value with its initial value, the atomic value is hidden until it ends
+func (builder *valueBuilder) startScalar(value interface{}) {
+	builder.scalarPolicy = builder.startPolicy()
+	switch builder.scalarPolicy {
+	case CompleteNull:
+		builder.add(nil)
+	case CompleteOmit:
+		builder.awaitingValue = false
+		if framesLen := len(builder.frames); framesLen > 0 && builder.frames[framesLen-1].object != nil {
+			// the member is omitted, the value of the same key before it is restored
+			frame := &builder.frames[framesLen-1]
+			if builder.keyExisted {
+				frame.object[frame.key] = builder.keyPrevious
+			} else {
+				delete(frame.object, frame.key)
+			}
+		}
+	default:
+		builder.add(value)
+	}
+}
+
+// end a string or number value, the omitted atomic value is added now
+func (builder *valueBuilder) endScalar(value interface{}) {
+	if builder.scalarPolicy == CompleteOmit {
+		builder.add(value)
+	} else {
+		builder.set(value)
+	}
+	builder.scalarPolicy = CompleteKeep
+}
+
+// store the partial string, key or number in lexing into the value
+func (builder *valueBuilder) flush() {
+	switch {
+	case builder.inString && builder.inKey:
+		if !builder.omitPendingKey {
+			builder.storePendingKey()
+		}
+	case builder.scalarPolicy != CompleteKeep:
+		// the atomic value is hidden
+	case builder.inString:
+		builder.set(builder.str.String())
+	case builder.inNumber:
+		builder.set(json.Number(appendCompletedNumber(nil, builder.number)))
+	}
+}
+
+func (builder *valueBuilder) onObjectStart() {
+	object := make(map[string]interface{})
+	builder.add(object)
+	builder.frames = append(builder.frames, valueFrame{object: object})
+}
+
+func (builder *valueBuilder) onObjectEnd() {
+	builder.frames = builder.frames[:len(builder.frames)-1]
+}
+
+func (builder *valueBuilder) onArrayStart() {
+	array := make([]interface{}, 0)
+	builder.add(array)
+	builder.frames = append(builder.frames, valueFrame{array: array})
+}
+
+func (builder *valueBuilder) onArrayEnd() {
+	builder.frames = builder.frames[:len(builder.frames)-1]
+}
+
+func (builder *valueBuilder) onStringStart(isKey bool) {
+	builder.inString = true
+	builder.inKey = isKey
+	if !isKey {
+		builder.startScalar("")
+	}
+}
+
+func (builder *valueBuilder) onStringByte(c byte) {
+	builder.str.WriteByte(c)
+}
+
+func (builder *valueBuilder) onStringEnd(isKey bool) {
+	if isKey {
+		builder.removePendingKey()
+		frame := &builder.frames[len(builder.frames)-1]
+		frame.key = builder.str.String()
+		builder.keyPrevious, builder.keyExisted = frame.object[frame.key]
+		frame.object[frame.key] = nil
+		builder.awaitingValue = true
+	} else {
+		builder.endScalar(builder.str.String())
+	}
+	builder.inString = false
+	builder.inKey = false
+	// reset drops the buffer, so the stored string is not overwritten by the next one
+	builder.str.Reset()
+}
+
+func (builder *valueBuilder) onNumberStart() {
+	builder.inNumber = true
+	builder.number = builder.number[:0]
+	builder.startScalar(nil)
+}
+
+func (builder *valueBuilder) onNumberByte(c byte) {
+	builder.number = append(builder.number, c)
+}
+
+func (builder *valueBuilder) onNumberEnd() {
+	builder.endScalar(json.Number(string(builder.number)))
+	builder.inNumber = false
+}
+
+func (builder *valueBuilder) onLiteralStart(literal string) {
+	builder.inLiteral = true
+	switch literal {
+	case tokenSymbolMap[TOKEN_TRUE]:
+		builder.add(true)
+	case tokenSymbolMap[TOKEN_FLASE]:
+		builder.add(false)
+	default:
+		builder.add(nil)
+	}
+}
+
+func (builder *valueBuilder) onLiteralEnd() {
+	builder.inLiteral = false
+}
+
+// get the open slot of the container at given depth, which holds the value in lexing,
+// it is the key for objects or the index for arrays, returns false if no value is in lexing in the container.
+// the partial key must be stored by flush() first
+func (builder *valueBuilder) openSlot(depth int) (string, int, bool) {
+	frame := &builder.frames[depth]
+	innermost := depth == len(builder.frames)-1
+	if innermost && !builder.inString && !builder.inNumber && !builder.inLiteral && !builder.awaitingValue {
+		return "", 0, false
+	}
+	if innermost && builder.scalarPolicy == CompleteOmit {
+		// the omitted atomic value has no slot yet
+		return "", 0, false
+	}
+	if frame.object == nil {
+		return "", len(frame.array) - 1, true
+	}
+	if innermost && builder.inKey {
+		if builder.omitPendingKey {
+			return "", 0, false
+		}
+		return builder.pendingKey, 0, true
+	}
+	return frame.key, 0, true
+}
+
+// attach a grammar listener, the JSON content received so far is replayed into the new listener only
+func (lexer *Lexer) attachListener(listener grammarListener) error {
+	listeners := append(lexer.grammar.listeners, listener)
+	lexer.grammar.reset()
+	lexer.grammar.listeners = listeners[len(listeners)-1:]
+	replayContent := func(content []byte) bool {
+		for _, c := range content {
+			if !lexer.grammar.feed(c) {
+				return false
+			}
+		}
+		return true
+	}
+	ok := replayContent(lexer.JSONContent.Bytes()) && replayContent(lexer.PaddingContent.Bytes())
+	if !ok {
+		lexer.grammar.listeners = listeners[:len(listeners)-1]
+		return errors.New("failed to replay json content")
+	}
+	lexer.grammar.listeners = listeners
+	return nil
+}
+
+// new value builder by options and atomic paths
+func (lexer *Lexer) newValueBuilder() *valueBuilder {
+	return &valueBuilder{
+		omitPendingKey: lexer.options.omitDanglingKeys,
+		atomicPolicy:   lexer.options.atomicPolicy,
+		atomicPaths:    lexer.atomicPaths,
+	}
+}
+
+// attach a value builder to the grammar
+func (lexer *Lexer) attachValueBuilder() error {
+	builder := lexer.newValueBuilder()
+	if err := lexer.attachListener(builder); err != nil {
+		return err
+	}
+	lexer.value = builder
+	return nil
+}
+
+// get the partial value of JSON stream, it equals the completed JSON decoded into interface{} with numbers as json.Number,
+// objects are map[string]interface{}, arrays are []interface{}, and it is nil if nothing appended yet.
+// the first call builds the value from the JSON content received so far, then it is updated as tokens arrive,
+// so the following calls are O(1). the returned maps and slices are owned by the lexer and keep changing with the stream.
+// an error is returned if the stream can never be a valid JSON.
+func (lexer *Lexer) Value() (interface{}, error) {
+	if lexer.grammarError != nil {
+		return nil, lexer.grammarError
+	}
+	if lexer.value == nil {
+		if err := lexer.attachValueBuilder(); err != nil {
+			return nil, err
+		}
+	}
+	lexer.value.flush()
+	if held, ok := lexer.heldValue(); ok {
+		policy := lexer.heldAtomicPolicy(held)
+		lexer.value.storeHeldValue(decodeHeldValue(held.value), policy)
+	}
+	return lexer.value.root, nil
+}
+
+// deep copy the partial value, so it does not change with the stream,
+// numbers are converted into float64 like encoding/json does if floatNumbers is set
+func copyValue(value interface{}, floatNumbers bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			object[key] = copyValue(element, floatNumbers)
+		}
+		return object
+	case []interface{}:
+		array := make([]interface{}, len(v))
+		for i, element := range v {
+			array[i] = copyValue(element, floatNumbers)
+		}
+		return array
+	case json.Number:
+		if floatNumbers {
+			f, _ := v.Float64()
+			return f
+		}
+	}
+	return value
+}
@@ -0,0 +1,104 @@
+package streamingjsongo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// decode the completed JSON as the expected value of Value()
+func decodeCompletedJSON(t *testing.T, completedJSON string) interface{} {
+	decoder := json.NewDecoder(strings.NewReader(completedJSON))
+	decoder.UseNumber()
+	var value interface{}
+	assert.Nil(t, decoder.Decode(&value), "invalid completed JSON: %s", completedJSON)
+	return value
+}
+
+func TestValue(t *testing.T) {
+	streamingJSONCase := map[string]interface{}{
+		``:                    nil,
+		`{`:                   map[string]interface{}{},
+		`{"`:                  map[string]interface{}{"": nil},
+		`{"ab`:                map[string]interface{}{"ab": nil},
+		`{"a":`:               map[string]interface{}{"a": nil},
+		`{"a":[tr`:            map[string]interface{}{"a": []interface{}{true}},
+		`{"a":[1, 2.`:         map[string]interface{}{"a": []interface{}{json.Number("1"), json.Number("2.0")}},
+		`{"a":-`:              map[string]interface{}{"a": json.Number("0")},
+		`{"a":1.5e`:           map[string]interface{}{"a": json.Number("1.5")},
+		`{"a":"x\nyé\u4`:      map[string]interface{}{"a": "x\nyé"},
+		`{"a":1,"b":{"c":[[n`: map[string]interface{}{"a": json.Number("1"), "b": map[string]interface{}{"c": []interface{}{[]interface{}{nil}}}},
+		`[{"a":"b"},{"c`:      []interface{}{map[string]interface{}{"a": "b"}, map[string]interface{}{"c": nil}},
+		`"str`:                "str",
+		`fals`:                false,
+	}
+	for testCase, expect := range streamingJSONCase {
+		lexer := NewLexer()
+		assert.Nil(t, lexer.AppendString(testCase))
+		value, err := lexer.Value()
+		assert.Nil(t, err)
+		assert.Equal(t, expect, value, "unexpected value in case: %s", testCase)
+	}
+}
+
+func TestValue_streaming(t *testing.T) {
+	streamingJSONContent := `{"string": "这是一个字符串", "integer": 42, "float": 3.14159, "boolean_true": true, "boolean_false": false, "null": null, "object": {"empty_object": {}, "non_empty_object": {"key": "value"}, "nested_object": {"nested_key": {"sub_nested_key": "sub_nested_value"}}}, "array":["string in array", 123, 45.67, true, false, null, {"object_in_array": "object_value"},["nested_array"]], "escaped": "\"\\\/\b\f\n\r\tA", "a":1, "ab":2, "a":[[-0.5e-3]]}`
+	// the value is attached before the stream and updated by each character
+	lexer := NewLexer()
+	_, _ = lexer.Value()
+	for i, c := range streamingJSONContent {
+		assert.Nil(t, lexer.AppendString(string(c)))
+		value, err := lexer.Value()
+		assert.Nil(t, err)
+		if !assert.Equal(t, decodeCompletedJSON(t, lexer.CompleteJSON()), value, "unexpected value at: %s", streamingJSONContent[:i+1]) {
+			break
+		}
+	}
+}
+
+func TestValue_attachedLater(t *testing.T) {
+	// the key is the JSON stream before Value() called, the value is the JSON segment appended after it
+	streamingJSONCase := map[string]string{
+		`{"a":[1, -`:    `2`,
+		`{"a":"b\`:      `n`,
+		`{"a":"b\u00`:   `41`,
+		`{"a":1.5e`:     `2`,
+		`{"a":1 , `:     `"b`,
+		`[[{"a":"b"}, `: `2`,
+	}
+	for testCase, segment := range streamingJSONCase {
+		lexer := NewLexer()
+		assert.Nil(t, lexer.AppendString(testCase))
+		value, err := lexer.Value()
+		assert.Nil(t, err)
+		assert.Equal(t, decodeCompletedJSON(t, lexer.CompleteJSON()), value, "unexpected value in case: %s", testCase)
+
+		// keep updating after attached
+		assert.Nil(t, lexer.AppendString(segment))
+		value, err = lexer.Value()
+		assert.Nil(t, err)
+		assert.Equal(t, decodeCompletedJSON(t, lexer.CompleteJSON()), value, "unexpected value in case: %s%s", testCase, segment)
+	}
+}
+
+func TestValue_invalid(t *testing.T) {
+	lexer := NewLexer()
+	assert.Nil(t, lexer.AppendString(`{"a":[1 2`))
+	value, err := lexer.Value()
+	assert.Nil(t, value)
+	syntaxError, ok := err.(*SyntaxError)
+	assert.True(t, ok)
+	assert.Equal(t, int64(8), syntaxError.Offset)
+	assert.Equal(t, `{"a":[1 2`, syntaxError.Excerpt)
+
+	// the lexer keeps completing in lenient mode
+	assert.Equal(t, `{"a":[1 2]}`, lexer.CompleteJSON())
+
+	lexer.Reset()
+	assert.Nil(t, lexer.AppendString(`[1`))
+	value, err = lexer.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{json.Number("1")}, value)
+}